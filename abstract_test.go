@@ -518,6 +518,7 @@ func TestResolveTypeOnInterfaceYieldsUsefulError(t *testing.T) {
 					"pets",
 					2,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -642,6 +643,7 @@ func TestResolveTypeOnUnionYieldsUsefulError(t *testing.T) {
 					"pets",
 					2,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -654,3 +656,174 @@ func TestResolveTypeOnUnionYieldsUsefulError(t *testing.T) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
+
+func petsSchema(t *testing.T, config graphql.SchemaConfig, petType graphql.Type, dogType, catType *graphql.Object, pets []interface{}) graphql.Schema {
+	config.Query = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"pets": &graphql.Field{
+				Type: graphql.NewList(petType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return pets, nil
+				},
+			},
+		},
+	})
+	config.Types = []graphql.Type{dogType, catType}
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func petObjects(dogConfig, catConfig graphql.ObjectConfig) (*graphql.Object, *graphql.Object) {
+	dogConfig.Name = "Dog"
+	dogConfig.Fields = graphql.Fields{
+		"name":  &graphql.Field{Type: graphql.String},
+		"woofs": &graphql.Field{Type: graphql.Boolean},
+	}
+	catConfig.Name = "Cat"
+	catConfig.Fields = graphql.Fields{
+		"name":  &graphql.Field{Type: graphql.String},
+		"meows": &graphql.Field{Type: graphql.Boolean},
+	}
+	return graphql.NewObject(dogConfig), graphql.NewObject(catConfig)
+}
+
+var expectedPets = &graphql.Result{
+	Data: map[string]interface{}{
+		"pets": []interface{}{
+			map[string]interface{}{"name": "Odie", "woofs": true},
+			map[string]interface{}{"name": "Garfield", "meows": false},
+		},
+	},
+}
+
+const petsQuery = `{
+	pets {
+		... on Dog { name woofs }
+		... on Cat { name meows }
+	}
+}`
+
+func TestGoTypesUsedToResolveRuntimeTypeForUnion(t *testing.T) {
+	dogType, catType := petObjects(
+		graphql.ObjectConfig{GoTypes: []reflect.Type{reflect.TypeOf(testDog{})}},
+		graphql.ObjectConfig{},
+	)
+	petType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "Pet",
+		Types: []*graphql.Object{dogType, catType},
+	})
+	schema := petsSchema(t, graphql.SchemaConfig{
+		GoTypes: map[reflect.Type]*graphql.Object{reflect.TypeOf(testCat{}): catType},
+	}, petType, dogType, catType, []interface{}{
+		&testDog{"Odie", true},
+		testCat{"Garfield", false},
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: petsQuery,
+	})
+	if !testutil.EqualResults(expectedPets, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedPets, result))
+	}
+}
+
+func TestGoTypesOfSchemaUsedToResolveRuntimeTypeForInterface(t *testing.T) {
+	petType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Pet",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	dogType, catType := petObjects(
+		graphql.ObjectConfig{Interfaces: []*graphql.Interface{petType}},
+		graphql.ObjectConfig{Interfaces: []*graphql.Interface{petType}},
+	)
+	schema := petsSchema(t, graphql.SchemaConfig{
+		GoTypes: map[reflect.Type]*graphql.Object{
+			reflect.TypeOf(testDog{}): dogType,
+			reflect.TypeOf(testCat{}): catType,
+		},
+	}, petType, dogType, catType, []interface{}{
+		&testDog{"Odie", true},
+		testCat{"Garfield", false},
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: petsQuery,
+	})
+	if !testutil.EqualResults(expectedPets, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedPets, result))
+	}
+}
+
+func TestTypeNameKeyUsedToResolveRuntimeTypeForInterface(t *testing.T) {
+	petType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Pet",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	dogType, catType := petObjects(
+		graphql.ObjectConfig{Interfaces: []*graphql.Interface{petType}},
+		graphql.ObjectConfig{Interfaces: []*graphql.Interface{petType}},
+	)
+	schema := petsSchema(t, graphql.SchemaConfig{}, petType, dogType, catType, []interface{}{
+		map[string]interface{}{"__typename": "Dog", "name": "Odie", "woofs": true},
+		map[string]interface{}{"__typename": "Cat", "name": "Garfield", "meows": false},
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: petsQuery,
+	})
+	if !testutil.EqualResults(expectedPets, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedPets, result))
+	}
+}
+
+func TestResolveTypeNameUsedToResolveRuntimeType(t *testing.T) {
+	dogType, catType := petObjects(graphql.ObjectConfig{}, graphql.ObjectConfig{})
+	petType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "Pet",
+		Types: []*graphql.Object{dogType, catType},
+		ResolveTypeName: func(p graphql.ResolveTypeParams) string {
+			if _, ok := p.Value.(*testDog); ok {
+				return "Dog"
+			}
+			return "Cat"
+		},
+	})
+	schema := petsSchema(t, graphql.SchemaConfig{}, petType, dogType, catType, []interface{}{
+		&testDog{"Odie", true},
+		&testCat{"Garfield", false},
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: petsQuery,
+	})
+	if !testutil.EqualResults(expectedPets, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedPets, result))
+	}
+}
+
+func TestGoTypesRegisteredForSeveralTypesFailSchema(t *testing.T) {
+	dogType, catType := petObjects(
+		graphql.ObjectConfig{GoTypes: []reflect.Type{reflect.TypeOf(testDog{})}},
+		graphql.ObjectConfig{GoTypes: []reflect.Type{reflect.TypeOf(testDog{})}},
+	)
+	_, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"pet": &graphql.Field{Type: dogType},
+			},
+		}),
+		Types: []graphql.Type{catType},
+	})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
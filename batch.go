@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// DoBatch executes the operations of a batch in order, and returns their
+// results in the same order.
+//
+// The operations share one context, the Context of the first Params, so that
+// the loaders and caches stored in it deduplicate work across the batch. An
+// operation failing does not fail the other ones. If the schema of the first
+// Params has a MaxBatchSize, and the batch is larger, no operation is
+// executed and each result holds a BATCH_LIMIT_EXCEEDED error.
+func DoBatch(params []Params) []*Result {
+	results := make([]*Result, len(params))
+	if len(params) == 0 {
+		return results
+	}
+	ctx := params[0].Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if max := params[0].Schema.maxBatchSize; max > 0 && len(params) > max {
+		err := newCodedError(
+			fmt.Errorf("Batch of %d operations exceeds the maximum of %d.", len(params), max),
+			gqlerrors.ErrCodeBatchLimitExceeded,
+		)
+		for i, p := range params {
+			results[i] = &Result{
+				Errors: presentErrors(ctx, errorPresenter(p.Schema, p.ErrorPresenter), err),
+			}
+		}
+		return results
+	}
+
+	for i, p := range params {
+		p.Context = ctx
+		results[i] = doBatchOperation(p)
+	}
+	return results
+}
+
+// doBatchOperation executes an operation of a batch, turning a panic into an
+// error of its result so that it does not fail the other operations.
+func doBatchOperation(p Params) (result *Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			result = &Result{
+				Errors: presentErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), newCodedError(err, gqlerrors.ErrCodeInternalServerError)),
+			}
+		}
+	}()
+	return Do(p)
+}
+
+// Request is a GraphQL request as sent in the JSON body of a HTTP request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+	DocumentID    string                 `json:"documentId,omitempty"`
+}
+
+// Params returns the Params to execute the request against the schema.
+func (r Request) Params(ctx context.Context, schema Schema) Params {
+	return Params{
+		Schema:         schema,
+		RequestString:  r.Query,
+		VariableValues: r.Variables,
+		OperationName:  r.OperationName,
+		Context:        ctx,
+		Extensions:     r.Extensions,
+		DocumentID:     r.DocumentID,
+	}
+}
+
+// DecodeRequests decodes a JSON body holding either a request or an array of
+// requests, and returns whether it is a batch, i.e. an array. The results of a
+// batch are to be sent as a JSON array.
+func DecodeRequests(r io.Reader) (requests []Request, batch bool, err error) {
+	return NewRequestDecoder(r).Decode()
+}
+
+// RequestDecoder decodes the requests of a JSON body, as DecodeRequests does,
+// with options.
+type RequestDecoder struct {
+	r         io.Reader
+	useNumber bool
+}
+
+// NewRequestDecoder returns a decoder reading the JSON body from r.
+func NewRequestDecoder(r io.Reader) *RequestDecoder {
+	return &RequestDecoder{r: r}
+}
+
+// UseNumber makes the decoder decode the numbers of the variables and
+// extensions as json.Number values rather than float64 ones, so that large
+// integers, e.g. 64-bit IDs, keep their precision. The custom scalars of the
+// schema then have to accept json.Number values, as the Int, Float and ID
+// scalars do.
+func (d *RequestDecoder) UseNumber() {
+	d.useNumber = true
+}
+
+// Decode decodes either a request or an array of requests, and returns
+// whether it is a batch, i.e. an array.
+func (d *RequestDecoder) Decode() (requests []Request, batch bool, err error) {
+	body, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return nil, false, err
+	}
+	body = bytes.TrimLeft(body, " \t\r\n")
+	if len(body) > 0 && body[0] == '[' {
+		if err := d.unmarshal(body, &requests); err != nil {
+			return nil, true, err
+		}
+		if len(requests) == 0 {
+			return nil, true, errors.New("empty batch of requests")
+		}
+		return requests, true, nil
+	}
+	var request Request
+	if err := d.unmarshal(body, &request); err != nil {
+		return nil, false, err
+	}
+	return []Request{request}, false, nil
+}
+
+func (d *RequestDecoder) unmarshal(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if d.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// json.Unmarshal rejects the data after the value, and so does Decode
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
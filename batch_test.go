@@ -0,0 +1,180 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type batchCacheKey struct{}
+
+func TestDoBatch_SharesTheContextOfTheBatch(t *testing.T) {
+	loads := map[string]int{}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						cache := p.Context.Value(batchCacheKey{}).(map[string]string)
+						id := p.Args["id"].(string)
+						if name, ok := cache[id]; ok {
+							return name, nil
+						}
+						loads[id]++
+						cache[id] = "user " + id
+						return cache[id], nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	ctx := context.WithValue(context.Background(), batchCacheKey{}, map[string]string{})
+	results := graphql.DoBatch([]graphql.Params{
+		{Schema: schema, RequestString: `{ user(id: "1") }`, Context: ctx},
+		{Schema: schema, RequestString: `{ a: user(id: "1") b: user(id: "2") }`},
+		{Schema: schema, RequestString: `{ user(id: "2") }`},
+	})
+	expected := []*graphql.Result{
+		{Data: map[string]interface{}{"user": "user 1"}},
+		{Data: map[string]interface{}{"a": "user 1", "b": "user 2"}},
+		{Data: map[string]interface{}{"user": "user 2"}},
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+	}
+	if expected := map[string]int{"1": 1, "2": 1}; !reflect.DeepEqual(expected, loads) {
+		t.Fatalf("Unexpected loads: %v", loads)
+	}
+}
+
+func TestDoBatch_IsolatesTheOperations(t *testing.T) {
+	results := graphql.DoBatch([]graphql.Params{
+		{Schema: testutil.StarWarsSchema, RequestString: `{ hero { name `},
+		{Schema: testutil.StarWarsSchema, RequestString: `{ hero { name } }`},
+		{Schema: testutil.StarWarsSchema, RequestString: `{ hero { unknown } }`},
+	})
+	if len(results) != 3 {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+	if len(results[0].Errors) != 1 || gqlerrors.Code(results[0].Errors[0]) != gqlerrors.ErrCodeParseFailed {
+		t.Fatalf("Unexpected errors: %v", results[0].Errors)
+	}
+	if len(results[1].Errors) != 0 || results[1].Data == nil {
+		t.Fatalf("Unexpected result: %v", results[1])
+	}
+	if len(results[2].Errors) != 1 || gqlerrors.Code(results[2].Errors[0]) != gqlerrors.ErrCodeValidationFailed {
+		t.Fatalf("Unexpected errors: %v", results[2].Errors)
+	}
+}
+
+func TestDoBatch_RejectsBatchesLargerThanMaxBatchSize(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:        testutil.StarWarsSchema.QueryType(),
+		MaxBatchSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	params := graphql.Params{Schema: schema, RequestString: `{ hero { name } }`}
+	if results := graphql.DoBatch([]graphql.Params{params, params}); len(results[1].Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", results[1].Errors)
+	}
+	results := graphql.DoBatch([]graphql.Params{params, params, params})
+	for _, result := range results {
+		if result.Data != nil || len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeBatchLimitExceeded {
+			t.Fatalf("Unexpected result: %v", result)
+		}
+	}
+}
+
+func TestDecodeRequests(t *testing.T) {
+	requests, batch, err := graphql.DecodeRequests(strings.NewReader(`
+		[{"query": "{ a }"}, {"query": "query B { b }", "operationName": "B", "variables": {"x": 1}}]
+	`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []graphql.Request{
+		{Query: "{ a }"},
+		{Query: "query B { b }", OperationName: "B", Variables: map[string]interface{}{"x": float64(1)}},
+	}
+	if !batch || !reflect.DeepEqual(expected, requests) {
+		t.Fatalf("Unexpected requests: %v, %v", requests, batch)
+	}
+
+	requests, batch, err = graphql.DecodeRequests(strings.NewReader(`{"query": "{ a }"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if batch || !reflect.DeepEqual([]graphql.Request{{Query: "{ a }"}}, requests) {
+		t.Fatalf("Unexpected requests: %v, %v", requests, batch)
+	}
+
+	for _, body := range []string{`[]`, `{"query": `, `"{ a }"`, `{"query": "{ a }"} {}`} {
+		if _, _, err := graphql.DecodeRequests(strings.NewReader(body)); err == nil {
+			t.Fatalf("expected an error for %v", body)
+		}
+	}
+}
+
+func TestRequestDecoder_UseNumber(t *testing.T) {
+	decoder := graphql.NewRequestDecoder(strings.NewReader(`{
+		"query": "query($id: ID, $count: Int, $ratio: Float) { echo(id: $id, count: $count, ratio: $ratio) }",
+		"variables": {"id": 9007199254740993, "count": 42, "ratio": 0.5}
+	}`))
+	decoder.UseNumber()
+	requests, _, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id := requests[0].Variables["id"]; id != json.Number("9007199254740993") {
+		t.Fatalf("Unexpected id: %#v", id)
+	}
+
+	var args map[string]interface{}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.ID},
+						"count": &graphql.ArgumentConfig{Type: graphql.Int},
+						"ratio": &graphql.ArgumentConfig{Type: graphql.Float},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						args = p.Args
+						return nil, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(requests[0].Params(context.Background(), schema))
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{"id": "9007199254740993", "count": 42, "ratio": 0.5}
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf("Unexpected arguments, Diff: %v", testutil.Diff(expected, args))
+	}
+}
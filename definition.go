@@ -217,6 +217,18 @@ type ParseValueFn func(value interface{}) interface{}
 // ParseLiteralFn is a function type for parsing the literal value of a GraphQLScalar type
 type ParseLiteralFn func(valueAST ast.Value) interface{}
 
+// SerializeWithErrorFn is like SerializeFn, but returns the reason why the
+// value can not be serialized
+type SerializeWithErrorFn func(value interface{}) (interface{}, error)
+
+// ParseValueWithErrorFn is like ParseValueFn, but returns the reason why the
+// value can not be parsed
+type ParseValueWithErrorFn func(value interface{}) (interface{}, error)
+
+// ParseLiteralWithErrorFn is like ParseLiteralFn, but returns the reason why
+// the literal value can not be parsed
+type ParseLiteralWithErrorFn func(valueAST ast.Value) (interface{}, error)
+
 // ScalarConfig options for creating a new GraphQLScalar
 type ScalarConfig struct {
 	Name         string `json:"name"`
@@ -224,6 +236,17 @@ type ScalarConfig struct {
 	Serialize    SerializeFn
 	ParseValue   ParseValueFn
 	ParseLiteral ParseLiteralFn
+
+	// SpecifiedByURL is the URL of the specification of the scalar, reported
+	// by the introspection.
+	SpecifiedByURL string `json:"specifiedByURL"`
+
+	// SerializeWithError, ParseValueWithError and ParseLiteralWithError are
+	// used instead of Serialize, ParseValue and ParseLiteral when set, so
+	// that the messages of their errors are reported to clients.
+	SerializeWithError    SerializeWithErrorFn
+	ParseValueWithError   ParseValueWithErrorFn
+	ParseLiteralWithError ParseLiteralWithErrorFn
 }
 
 // NewScalar creates a new GraphQLScalar
@@ -245,7 +268,7 @@ func NewScalar(config ScalarConfig) *Scalar {
 	st.PrivateDescription = config.Description
 
 	err = invariantf(
-		config.Serialize != nil,
+		config.Serialize != nil || config.SerializeWithError != nil,
 		`%v must provide "serialize" function. If this custom Scalar is `+
 			`also used as an input type, ensure "parseValue" and "parseLiteral" `+
 			`functions are also provided.`, st,
@@ -254,9 +277,11 @@ func NewScalar(config ScalarConfig) *Scalar {
 		st.err = err
 		return st
 	}
-	if config.ParseValue != nil || config.ParseLiteral != nil {
+	hasParseValue := config.ParseValue != nil || config.ParseValueWithError != nil
+	hasParseLiteral := config.ParseLiteral != nil || config.ParseLiteralWithError != nil
+	if hasParseValue || hasParseLiteral {
 		err = invariantf(
-			config.ParseValue != nil && config.ParseLiteral != nil,
+			hasParseValue && hasParseLiteral,
 			`%v must provide both "parseValue" and "parseLiteral" functions.`, st,
 		)
 		if err != nil {
@@ -269,26 +294,61 @@ func NewScalar(config ScalarConfig) *Scalar {
 	return st
 }
 func (st *Scalar) Serialize(value interface{}) interface{} {
+	serialized, _ := st.SerializeWithError(value)
+	return serialized
+}
+func (st *Scalar) ParseValue(value interface{}) interface{} {
+	parsed, _ := st.ParseValueWithError(value)
+	return parsed
+}
+func (st *Scalar) ParseLiteral(valueAST ast.Value) interface{} {
+	parsed, _ := st.ParseLiteralWithError(valueAST)
+	return parsed
+}
+
+// SerializeWithError serializes the value, returning the error of the
+// SerializeWithError function of the scalar if it has one.
+func (st *Scalar) SerializeWithError(value interface{}) (interface{}, error) {
+	if st.scalarConfig.SerializeWithError != nil {
+		return st.scalarConfig.SerializeWithError(value)
+	}
 	if st.scalarConfig.Serialize == nil {
-		return value
+		return value, nil
 	}
-	return st.scalarConfig.Serialize(value)
+	return st.scalarConfig.Serialize(value), nil
 }
-func (st *Scalar) ParseValue(value interface{}) interface{} {
+
+// ParseValueWithError parses the value, returning the error of the
+// ParseValueWithError function of the scalar if it has one.
+func (st *Scalar) ParseValueWithError(value interface{}) (interface{}, error) {
+	if st.scalarConfig.ParseValueWithError != nil {
+		return st.scalarConfig.ParseValueWithError(value)
+	}
 	if st.scalarConfig.ParseValue == nil {
-		return value
+		return value, nil
 	}
-	return st.scalarConfig.ParseValue(value)
+	return st.scalarConfig.ParseValue(value), nil
 }
-func (st *Scalar) ParseLiteral(valueAST ast.Value) interface{} {
+
+// ParseLiteralWithError parses the literal value, returning the error of the
+// ParseLiteralWithError function of the scalar if it has one.
+func (st *Scalar) ParseLiteralWithError(valueAST ast.Value) (interface{}, error) {
+	if st.scalarConfig.ParseLiteralWithError != nil {
+		return st.scalarConfig.ParseLiteralWithError(valueAST)
+	}
 	if st.scalarConfig.ParseLiteral == nil {
-		return nil
+		return nil, nil
 	}
-	return st.scalarConfig.ParseLiteral(valueAST)
+	return st.scalarConfig.ParseLiteral(valueAST), nil
 }
 func (st *Scalar) Name() string {
 	return st.PrivateName
 }
+
+// SpecifiedByURL returns the URL of the specification of the scalar.
+func (st *Scalar) SpecifiedByURL() string {
+	return st.scalarConfig.SpecifiedByURL
+}
 func (st *Scalar) Description() string {
 	return st.PrivateDescription
 
@@ -374,6 +434,14 @@ type ObjectConfig struct {
 	Fields      interface{} `json:"fields"`
 	IsTypeOf    IsTypeOfFn  `json:"isTypeOf"`
 	Description string      `json:"description"`
+
+	// FieldMiddleware wraps the resolver of every field of the type, inside
+	// the middleware of the schema.
+	FieldMiddleware []FieldMiddleware `json:"-"`
+
+	// GoTypes are the Go types of the values of the type, used to resolve
+	// the runtime type of the values of abstract types.
+	GoTypes []reflect.Type `json:"-"`
 }
 
 type FieldsThunk func() Fields
@@ -415,6 +483,12 @@ func (gt *Object) AddFieldConfig(fieldName string, fieldConfig *Field) {
 		gt.initialisedFields = false
 	}
 }
+
+// AddFieldMiddleware adds middleware around the resolver of every field of
+// the type.
+func (gt *Object) AddFieldMiddleware(m ...FieldMiddleware) {
+	gt.typeConfig.FieldMiddleware = append(gt.typeConfig.FieldMiddleware, m...)
+}
 func (gt *Object) Name() string {
 	return gt.PrivateName
 }
@@ -680,6 +754,7 @@ type Interface struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
 	ResolveType        ResolveTypeFn
+	ResolveTypeName    ResolveTypeNameFn
 
 	typeConfig        InterfaceConfig
 	initialisedFields bool
@@ -687,10 +762,11 @@ type Interface struct {
 	err               error
 }
 type InterfaceConfig struct {
-	Name        string      `json:"name"`
-	Fields      interface{} `json:"fields"`
-	ResolveType ResolveTypeFn
-	Description string `json:"description"`
+	Name            string      `json:"name"`
+	Fields          interface{} `json:"fields"`
+	ResolveType     ResolveTypeFn
+	ResolveTypeName ResolveTypeNameFn
+	Description     string `json:"description"`
 }
 
 // ResolveTypeParams Params for ResolveTypeFn()
@@ -710,6 +786,10 @@ type ResolveTypeParams struct {
 
 type ResolveTypeFn func(p ResolveTypeParams) *Object
 
+// ResolveTypeNameFn is like ResolveTypeFn, but returns the name of the Object
+// type.
+type ResolveTypeNameFn func(p ResolveTypeParams) string
+
 func NewInterface(config InterfaceConfig) *Interface {
 	it := &Interface{}
 
@@ -722,6 +802,7 @@ func NewInterface(config InterfaceConfig) *Interface {
 	it.PrivateName = config.Name
 	it.PrivateDescription = config.Description
 	it.ResolveType = config.ResolveType
+	it.ResolveTypeName = config.ResolveTypeName
 	it.typeConfig = config
 
 	return it
@@ -795,6 +876,7 @@ type Union struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
 	ResolveType        ResolveTypeFn
+	ResolveTypeName    ResolveTypeNameFn
 
 	typeConfig      UnionConfig
 	initalizedTypes bool
@@ -807,10 +889,11 @@ type Union struct {
 type UnionTypesThunk func() []*Object
 
 type UnionConfig struct {
-	Name        string      `json:"name"`
-	Types       interface{} `json:"types"`
-	ResolveType ResolveTypeFn
-	Description string `json:"description"`
+	Name            string      `json:"name"`
+	Types           interface{} `json:"types"`
+	ResolveType     ResolveTypeFn
+	ResolveTypeName ResolveTypeNameFn
+	Description     string `json:"description"`
 }
 
 func NewUnion(config UnionConfig) *Union {
@@ -825,6 +908,7 @@ func NewUnion(config UnionConfig) *Union {
 	objectType.PrivateName = config.Name
 	objectType.PrivateDescription = config.Description
 	objectType.ResolveType = config.ResolveType
+	objectType.ResolveTypeName = config.ResolveTypeName
 
 	objectType.typeConfig = config
 
@@ -871,17 +955,6 @@ func defineUnionTypes(objectType *Union, unionTypes []*Object) ([]*Object, error
 		); err != nil {
 			return definedUnionTypes, err
 		}
-		if objectType.ResolveType == nil {
-			if err := invariantf(
-				ttype.IsTypeOf != nil,
-				`Union Type %v does not provide a "resolveType" function `+
-					`and possible Type %v does not provide a "isTypeOf" `+
-					`function. There is no way to resolve this possible type `+
-					`during execution.`, objectType, ttype,
-			); err != nil {
-				return definedUnionTypes, err
-			}
-		}
 		definedUnionTypes = append(definedUnionTypes, ttype)
 	}
 
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DocumentCache caches the parsed documents of the requests, along with the
+// outcome of their validation, so that Do does not parse and validate the
+// same request string again. It is bounded, evicting the least recently used
+// documents first, and safe for concurrent use.
+//
+// A DocumentCache can be set on the SchemaConfig, or per request on the
+// Params, and can be shared by several schemas.
+type DocumentCache struct {
+	// accessed atomically, first in the struct for 64-bit alignment.
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	lru *lruCache
+}
+
+// DocumentCacheStats are the statistics of a DocumentCache.
+type DocumentCacheStats struct {
+	// Hits is the number of requests whose document was found in the cache.
+	Hits uint64
+	// Misses is the number of requests whose document was parsed.
+	Misses uint64
+	// Evictions is the number of documents evicted from the cache.
+	Evictions uint64
+	// Len is the number of documents in the cache.
+	Len int
+}
+
+// NewDocumentCache returns a DocumentCache holding at most size documents.
+func NewDocumentCache(size int) *DocumentCache {
+	return &DocumentCache{
+		lru: newLRUCache(size),
+	}
+}
+
+// Stats returns the statistics of the cache.
+func (c *DocumentCache) Stats() DocumentCacheStats {
+	return DocumentCacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+		Len:       c.lru.len(),
+	}
+}
+
+// cachedDocument is a parsed document and the errors of its validation.
+type cachedDocument struct {
+	document         *ast.Document
+	validationErrors []gqlerrors.FormattedError
+}
+
+func documentCacheKey(schema Schema, requestString string) string {
+	return strconv.FormatUint(schema.id, 10) + ":" + requestString
+}
+
+func (c *DocumentCache) get(schema Schema, requestString string) *cachedDocument {
+	if value, ok := c.lru.get(documentCacheKey(schema, requestString)); ok {
+		atomic.AddUint64(&c.hits, 1)
+		return value.(*cachedDocument)
+	}
+	atomic.AddUint64(&c.misses, 1)
+	return nil
+}
+
+func (c *DocumentCache) add(schema Schema, requestString string, doc *cachedDocument) {
+	if c.lru.add(documentCacheKey(schema, requestString), doc) {
+		atomic.AddUint64(&c.evictions, 1)
+	}
+}
+
+// documentCache returns the cache of the request, or the one of the schema
+// if the request does not set one.
+func documentCache(schema Schema, cache *DocumentCache) *DocumentCache {
+	if cache != nil {
+		return cache
+	}
+	return schema.documentCache
+}
+
+type documentCacheHitKey struct{}
+
+// IsDocumentCacheHit returns whether the document of the request was found
+// in the DocumentCache, and so was neither parsed nor validated again. It
+// can be called by extensions with the context passed to ParseDidStart and
+// the later hooks.
+func IsDocumentCacheHit(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	hit, _ := ctx.Value(documentCacheHitKey{}).(bool)
+	return hit
+}
+
+func withDocumentCacheHit(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, documentCacheHitKey{}, true)
+}
@@ -0,0 +1,111 @@
+package graphql_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestDocumentCache_CachesParsedAndValidatedDocuments(t *testing.T) {
+	cache := graphql.NewDocumentCache(10)
+	schema := tinit(t)
+
+	var parseHits, validationHits []bool
+	ext := newtestExt("cacheExt")
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		parseHits = append(parseHits, graphql.IsDocumentCacheHit(ctx))
+		return ctx, func(err error) {}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		validationHits = append(validationHits, graphql.IsDocumentCacheHit(ctx))
+		return ctx, func([]gqlerrors.FormattedError) {}
+	}
+	schema.AddExtensions(ext)
+
+	for i := 0; i < 3; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ a }`,
+			DocumentCache: cache,
+		})
+		expected := &graphql.Result{
+			Data: map[string]interface{}{"a": "foo"},
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+
+	expectedHits := []bool{false, true, true}
+	if !reflect.DeepEqual(expectedHits, parseHits) || !reflect.DeepEqual(expectedHits, validationHits) {
+		t.Fatalf("Unexpected cache hits reported to extensions: %v, %v", parseHits, validationHits)
+	}
+	expectedStats := graphql.DocumentCacheStats{Hits: 2, Misses: 1, Len: 1}
+	if stats := cache.Stats(); !reflect.DeepEqual(expectedStats, stats) {
+		t.Fatalf("Unexpected stats, Diff: %v", testutil.Diff(expectedStats, stats))
+	}
+}
+
+func TestDocumentCache_CachesValidationErrors(t *testing.T) {
+	base := tinit(t)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:         base.QueryType(),
+		DocumentCache: graphql.NewDocumentCache(10),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	var results []*graphql.Result
+	for i := 0; i < 2; i++ {
+		results = append(results, graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ unknown }`,
+		}))
+	}
+	if len(results[0].Errors) != 1 || gqlerrors.Code(results[0].Errors[0]) != gqlerrors.ErrCodeValidationFailed {
+		t.Fatalf("Unexpected errors: %v", results[0].Errors)
+	}
+	if !reflect.DeepEqual(results[0], results[1]) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(results[0], results[1]))
+	}
+}
+
+func TestDocumentCache_IsKeyedBySchema(t *testing.T) {
+	cache := graphql.NewDocumentCache(10)
+	for _, schema := range []graphql.Schema{tinit(t), testutil.StarWarsSchema} {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ a }`,
+			DocumentCache: cache,
+		})
+		if schema.QueryType().Name() == "Type" && len(result.Errors) != 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if schema.QueryType().Name() != "Type" && len(result.Errors) != 1 {
+			t.Fatalf("Expected a validation error, got %v", result.Errors)
+		}
+	}
+	if stats := cache.Stats(); stats.Misses != 2 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestDocumentCache_EvictsLeastRecentlyUsedDocuments(t *testing.T) {
+	cache := graphql.NewDocumentCache(2)
+	schema := tinit(t)
+	for _, query := range []string{`{ a }`, `{ erred }`, `{ a }`, `{ b: a }`, `{ a }`, `{ erred }`} {
+		graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+			DocumentCache: cache,
+		})
+	}
+	expected := graphql.DocumentCacheStats{Hits: 2, Misses: 4, Evictions: 2, Len: 2}
+	if stats := cache.Stats(); !reflect.DeepEqual(expected, stats) {
+		t.Fatalf("Unexpected stats, Diff: %v", testutil.Diff(expected, stats))
+	}
+}
@@ -297,7 +297,7 @@ func TestTypeSystem_EnumValues_DoesNotAcceptInternalValueAsEnumVariable(t *testi
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Variable \"$color\" got invalid value 2.\nExpected type \"Color\", found \"2\".",
+				Message: `Variable "$color" got invalid value 2; Expected type "Color", found "2".`,
 				Locations: []location.SourceLocation{
 					{Line: 1, Column: 12},
 				},
@@ -0,0 +1,113 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestErrorCodes_ParseError(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero `,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if code := gqlerrors.Code(result.Errors[0]); code != gqlerrors.ErrCodeParseFailed {
+		t.Fatalf("Unexpected code: %v", code)
+	}
+}
+
+func TestErrorCodes_ValidationErrorCarriesRuleName(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero { unknown } }`,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{
+		"code": gqlerrors.ErrCodeValidationFailed,
+		"rule": "FieldsOnCorrectTypeRule",
+	}
+	if !reflect.DeepEqual(expected, result.Errors[0].Extensions) {
+		t.Fatalf("Unexpected extensions, Diff: %v", testutil.Diff(expected, result.Errors[0].Extensions))
+	}
+}
+
+func TestErrorCodes_BadUserInput(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:         testutil.StarWarsSchema,
+		RequestString:  `query ($id: String!) { human(id: $id) { name } }`,
+		VariableValues: map[string]interface{}{},
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if code := gqlerrors.Code(result.Errors[0]); code != gqlerrors.ErrCodeBadUserInput {
+		t.Fatalf("Unexpected code: %v", code)
+	}
+}
+
+func TestErrorCodes_UnknownOperationName(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `query A { hero { name } }`,
+		OperationName: "B",
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if code := gqlerrors.Code(result.Errors[0]); code != gqlerrors.ErrCodeOperationResolutionFailure {
+		t.Fatalf("Unexpected code: %v", code)
+	}
+}
+
+func TestErrorCodes_ResolverErrorKeepsOwnCode(t *testing.T) {
+	result := testErrors(t, graphql.String, map[string]interface{}{"code": "CAN_NOT_FETCH_BY_ID"}, nil)
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if code := gqlerrors.Code(result.Errors[0]); code != "CAN_NOT_FETCH_BY_ID" {
+		t.Fatalf("Unexpected code: %v", code)
+	}
+}
+
+func TestErrorCodes_DoNotChangeSharedErrors(t *testing.T) {
+	errNotFound := gqlerrors.NewError("not found", nil, "", nil, []int{}, nil)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errNotFound
+					},
+				},
+				"b": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errNotFound
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	for _, query := range []string{`{ a }`, `{ b }`} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+		if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeResolverError {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	}
+	if errNotFound.Extensions != nil {
+		t.Fatalf("Expected the shared error to be unchanged, got extensions %v", errNotFound.Extensions)
+	}
+}
@@ -0,0 +1,93 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// ErrorPresenterFn is called for every error that ends up in a Result, and
+// returns the error as it should be presented to the client.
+//
+// The error passed to the presenter keeps its original cause, so
+// `errors.As` / `errors.Is` can be used to find it, e.g. to map an internal
+// database error to a safe public message, or to attach a trace ID to the
+// error extensions.
+//
+// Example:
+//
+//	func presenter(ctx context.Context, err error) gqlerrors.FormattedError {
+//	    formatted := gqlerrors.FormatError(err)
+//	    var sqlErr *pq.Error
+//	    if errors.As(err, &sqlErr) {
+//	        formatted.Message = "internal error"
+//	    }
+//	    return formatted
+//	}
+type ErrorPresenterFn func(ctx context.Context, err error) gqlerrors.FormattedError
+
+// DefaultErrorPresenter formats the error without altering it.
+func DefaultErrorPresenter(ctx context.Context, err error) gqlerrors.FormattedError {
+	return gqlerrors.FormatError(err)
+}
+
+// presentError formats err with the given presenter, falling back to the
+// DefaultErrorPresenter if none is given.
+func presentError(ctx context.Context, presenter ErrorPresenterFn, err error) gqlerrors.FormattedError {
+	if presenter == nil {
+		presenter = DefaultErrorPresenter
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return presenter(ctx, err)
+}
+
+// presentErrors formats each of the errs with the given presenter, including
+// each of the errors of a multiError.
+func presentErrors(ctx context.Context, presenter ErrorPresenterFn, errs ...error) []gqlerrors.FormattedError {
+	formattedErrors := []gqlerrors.FormattedError{}
+	for _, err := range errs {
+		if multiErr, ok := err.(multiError); ok {
+			formattedErrors = append(formattedErrors, presentErrors(ctx, presenter, multiErr...)...)
+			continue
+		}
+		formattedErrors = append(formattedErrors, presentError(ctx, presenter, err))
+	}
+	return formattedErrors
+}
+
+// multiError is a group of errors, which are reported separately, e.g. the
+// errors of the variables of a request.
+type multiError []error
+
+func (errs multiError) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// presentFormattedErrors runs already formatted errors (e.g. validation
+// errors) through the given presenter.
+func presentFormattedErrors(ctx context.Context, presenter ErrorPresenterFn, errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	if presenter == nil {
+		return errs
+	}
+	formattedErrors := make([]gqlerrors.FormattedError, 0, len(errs))
+	for _, err := range errs {
+		formattedErrors = append(formattedErrors, presentError(ctx, presenter, err))
+	}
+	return formattedErrors
+}
+
+// errorPresenter returns the presenter of the request, or the one of the
+// schema if the request does not override it.
+func errorPresenter(schema Schema, presenter ErrorPresenterFn) ErrorPresenterFn {
+	if presenter != nil {
+		return presenter
+	}
+	return schema.errorPresenter
+}
@@ -0,0 +1,199 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type driverError struct {
+	query string
+}
+
+func (e *driverError) Error() string {
+	return "pq: relation \"users\" does not exist: " + e.query
+}
+
+func maskingPresenter(ctx context.Context, err error) gqlerrors.FormattedError {
+	formatted := gqlerrors.FormatError(err)
+	var dErr *driverError
+	if errors.As(err, &dErr) {
+		formatted.Message = "Internal server error."
+		formatted.Extensions["traceId"] = "abc"
+	}
+	return formatted
+}
+
+func errorPresenterSchema(t *testing.T, presenter graphql.ErrorPresenterFn) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"users": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, &driverError{query: "SELECT * FROM users"}
+					},
+				},
+				"nonNullUsers": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, &driverError{query: "SELECT * FROM users"}
+					},
+				},
+				"plain": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("plain error")
+					},
+				},
+			},
+		}),
+		ErrorPresenter: presenter,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestErrorPresenter_MasksResolverErrors(t *testing.T) {
+	schema := errorPresenterSchema(t, maskingPresenter)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ users }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"users": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "Internal server error.",
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []interface{}{"users"},
+				Extensions: map[string]interface{}{
+					"code":    gqlerrors.ErrCodeResolverError,
+					"traceId": "abc",
+				},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ plain }`,
+	})
+	expected = &graphql.Result{
+		Data: map[string]interface{}{
+			"plain": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "plain error",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:       []interface{}{"plain"},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestErrorPresenter_PresentsNonNullErrorsOnce(t *testing.T) {
+	calls := 0
+	schema := errorPresenterSchema(t, func(ctx context.Context, err error) gqlerrors.FormattedError {
+		calls++
+		return maskingPresenter(ctx, err)
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ nonNullUsers }`,
+	})
+	if result.Data != nil {
+		t.Fatalf("expected nil data, got %v", result.Data)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the presenter to be called once, got %v", calls)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "Internal server error." {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if !reflect.DeepEqual(result.Errors[0].Path, []interface{}{"nonNullUsers"}) {
+		t.Fatalf("Unexpected path: %v", result.Errors[0].Path)
+	}
+}
+
+func TestErrorPresenter_RequestOverridesSchema(t *testing.T) {
+	schema := errorPresenterSchema(t, maskingPresenter)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ users }`,
+		ErrorPresenter: func(ctx context.Context, err error) gqlerrors.FormattedError {
+			formatted := gqlerrors.FormatError(err)
+			formatted.Message = "request presenter"
+			return formatted
+		},
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "request presenter" {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestErrorPresenter_PresentsParseAndValidationErrors(t *testing.T) {
+	var presented []error
+	schema := errorPresenterSchema(t, func(ctx context.Context, err error) gqlerrors.FormattedError {
+		presented = append(presented, err)
+		formatted := gqlerrors.FormatError(err)
+		formatted.Message = "masked"
+		return formatted
+	})
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ users `,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "masked" {
+		t.Fatalf("Unexpected parse errors: %v", result.Errors)
+	}
+	if len(result.Errors[0].Locations) != 1 {
+		t.Fatalf("Expected the parse error location to be kept, got %v", result.Errors[0].Locations)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ unknown }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "masked" {
+		t.Fatalf("Unexpected validation errors: %v", result.Errors)
+	}
+	if len(presented) != 2 {
+		t.Fatalf("Expected 2 presented errors, got %v", len(presented))
+	}
+}
+
+func TestErrorPresenter_UnwrapsOriginalError(t *testing.T) {
+	schema := errorPresenterSchema(t, nil)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ users }`,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	var dErr *driverError
+	if !errors.As(result.Errors[0], &dErr) {
+		t.Fatalf("expected errors.As to find the resolver error in %#v", result.Errors[0])
+	}
+}
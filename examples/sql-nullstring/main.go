@@ -5,95 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/graphql-go/graphql"
-	"github.com/graphql-go/graphql/language/ast"
 	"log"
 )
 
-// NullString to be used in place of sql.NullString
-type NullString struct {
-	sql.NullString
-}
-
-// MarshalJSON from the json.Marshaler interface
-func (v NullString) MarshalJSON() ([]byte, error) {
-	if v.Valid {
-		return json.Marshal(v.String)
-	}
-	return json.Marshal(nil)
-}
-
-// UnmarshalJSON from the json.Unmarshaler interface
-func (v *NullString) UnmarshalJSON(data []byte) error {
-	var x *string
-	if err := json.Unmarshal(data, &x); err != nil {
-		return err
-	}
-	if x != nil {
-		v.String = *x
-		v.Valid = true
-	} else {
-		v.Valid = false
-	}
-	return nil
-}
-
-// NewNullString create a new null string. Empty string evaluates to an
-// "invalid" NullString
-func NewNullString(value string) *NullString {
-	var null NullString
-	if value != "" {
-		null.String = value
-		null.Valid = true
-		return &null
-	}
-	null.Valid = false
-	return &null
-}
-
-// SerializeNullString serializes `NullString` to a string
-func SerializeNullString(value interface{}) interface{} {
-	switch value := value.(type) {
-	case NullString:
-		return value.String
-	case *NullString:
-		v := *value
-		return v.String
-	default:
-		return nil
-	}
-}
-
-// ParseNullString parses GraphQL variables from `string` to `CustomID`
-func ParseNullString(value interface{}) interface{} {
-	switch value := value.(type) {
-	case string:
-		return NewNullString(value)
-	case *string:
-		return NewNullString(*value)
-	default:
-		return nil
-	}
-}
-
-// ParseLiteralNullString parses GraphQL AST value to `NullString`.
-func ParseLiteralNullString(valueAST ast.Value) interface{} {
-	switch valueAST := valueAST.(type) {
-	case *ast.StringValue:
-		return NewNullString(valueAST.Value)
-	default:
-		return nil
-	}
-}
-
-// NullableString graphql *Scalar type based of NullString
-var NullableString = graphql.NewScalar(graphql.ScalarConfig{
-	Name:         "NullableString",
-	Description:  "The `NullableString` type repesents a nullable SQL string.",
-	Serialize:    SerializeNullString,
-	ParseValue:   ParseNullString,
-	ParseLiteral: ParseLiteralNullString,
-})
-
 /*
 CREATE TABLE persons (
 	favorite_dog TEXT -- is a nullable field
@@ -103,8 +17,8 @@ CREATE TABLE persons (
 
 // Person noqa
 type Person struct {
-	Name        string      `json:"name"`
-	FavoriteDog *NullString `json:"favorite_dog"` // Some people don't like dogs ¯\_(ツ)_/¯
+	Name        string         `json:"name"`
+	FavoriteDog sql.NullString `json:"favorite_dog"` // Some people don't like dogs ¯\_(ツ)_/¯
 }
 
 // PersonType noqa
@@ -115,11 +29,17 @@ var PersonType = graphql.NewObject(graphql.ObjectConfig{
 			Type: graphql.String,
 		},
 		"favorite_dog": &graphql.Field{
-			Type: NullableString,
+			Type: graphql.String,
 		},
 	},
 })
 
+// NewNullString create a new null string. Empty string evaluates to an
+// "invalid" NullString
+func NewNullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
 func main() {
 	schema, err := graphql.NewSchema(graphql.SchemaConfig{
 		Query: graphql.NewObject(graphql.ObjectConfig{
@@ -129,11 +49,11 @@ func main() {
 					Type: graphql.NewList(PersonType),
 					Args: graphql.FieldConfigArgument{
 						"favorite_dog": &graphql.ArgumentConfig{
-							Type: NullableString,
+							Type: graphql.String,
 						},
 					},
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-						dog, dogOk := p.Args["favorite_dog"].(*NullString)
+						dog := p.Args["favorite_dog"].(sql.NullString)
 						people := []Person{
 							Person{Name: "Alice", FavoriteDog: NewNullString("Yorkshire Terrier")},
 							// `Bob`'s favorite dog will be saved as null in the database
@@ -141,7 +61,7 @@ func main() {
 							Person{Name: "Chris", FavoriteDog: NewNullString("French Bulldog")},
 						}
 						switch {
-						case dogOk:
+						case dog.Valid:
 							log.Printf("favorite_dog from arguments: %+v", dog)
 							dogPeople := make([]Person, 0)
 							for _, p := range people {
@@ -159,6 +79,8 @@ func main() {
 				},
 			},
 		}),
+		// pass the nullable arguments as sql.NullString values
+		SQLNullArguments: true,
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -223,7 +145,7 @@ Result: {
         "name": "Alice"
       },
       {
-        "favorite_dog": "",
+        "favorite_dog": null,
         "name": "Bob"
       },
       {
@@ -2,10 +2,8 @@ package graphql
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
-	"sort"
 	"strings"
 
 	"github.com/graphql-go/graphql/gqlerrors"
@@ -22,6 +20,40 @@ type ExecuteParams struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// ErrorPresenter may be provided to override the ErrorPresenter of the
+	// schema for this execution.
+	ErrorPresenter ErrorPresenterFn
+
+	// OrderedResults, if set, makes the objects of the Result data
+	// *OrderedMap values, which keep the fields in the order of the
+	// selection set, instead of `map[string]interface{}` values.
+	OrderedResults bool
+
+	// FieldResolver, if set, is the resolve function of the fields without
+	// one, instead of DefaultResolveFn.
+	FieldResolver FieldResolveFn
+
+	// FieldSubscriber, if set, is the Subscribe function of the root
+	// subscription fields without one, see ExecuteSubscription.
+	FieldSubscriber FieldResolveFn
+
+	// prepared is set when executing a PreparedOperation, whose plan is used
+	// instead of the one of the AST.
+	prepared *PreparedOperation
+}
+
+// Operation returns the operation of the params, nil if it can not be
+// selected, in which case Execute reports the error.
+func (p ExecuteParams) Operation() *ast.OperationDefinition {
+	if p.AST == nil {
+		return nil
+	}
+	operation, _, err := selectOperation(p.AST, p.OperationName)
+	if err != nil {
+		return nil
+	}
+	return operation
 }
 
 func Execute(p ExecuteParams) (result *Result) {
@@ -30,6 +62,8 @@ func Execute(p ExecuteParams) (result *Result) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
+	presenter := errorPresenter(p.Schema, p.ErrorPresenter)
+
 	// run executionDidStart functions from extensions
 	extErrs, executionFinishFn := handleExtensionsExecutionDidStart(&p)
 	if len(extErrs) != 0 {
@@ -54,23 +88,27 @@ func Execute(p ExecuteParams) (result *Result) {
 
 		defer func() {
 			if err := recover(); err != nil {
-				result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
+				result.Errors = append(result.Errors, presentError(p.Context, presenter, newCodedError(err.(error), gqlerrors.ErrCodeInternalServerError)))
 			}
 			resultChannel <- result
 		}()
 
 		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
-			Schema:        p.Schema,
-			Root:          p.Root,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Result:        result,
-			Context:       p.Context,
+			Schema:         p.Schema,
+			Root:           p.Root,
+			AST:            p.AST,
+			OperationName:  p.OperationName,
+			Args:           p.Args,
+			Result:         result,
+			Context:        p.Context,
+			ErrorPresenter: presenter,
+			OrderedResults: p.OrderedResults,
+			Prepared:       p.prepared,
+			FieldResolver:  p.FieldResolver,
 		})
 
 		if err != nil {
-			result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
+			result.Errors = append(result.Errors, presentErrors(p.Context, presenter, err)...)
 			resultChannel <- result
 			return
 		}
@@ -85,7 +123,7 @@ func Execute(p ExecuteParams) (result *Result) {
 	select {
 	case <-ctx.Done():
 		result := &Result{}
-		result.Errors = append(result.Errors, gqlerrors.FormatError(ctx.Err()))
+		result.Errors = append(result.Errors, presentError(ctx, presenter, newCodedError(ctx.Err(), gqlerrors.ErrCodeExecutionCanceled)))
 		return result
 	case r := <-resultChannel:
 		return r
@@ -93,13 +131,17 @@ func Execute(p ExecuteParams) (result *Result) {
 }
 
 type buildExecutionCtxParams struct {
-	Schema        Schema
-	Root          interface{}
-	AST           *ast.Document
-	OperationName string
-	Args          map[string]interface{}
-	Result        *Result
-	Context       context.Context
+	Schema         Schema
+	Root           interface{}
+	AST            *ast.Document
+	OperationName  string
+	Args           map[string]interface{}
+	Result         *Result
+	Context        context.Context
+	ErrorPresenter ErrorPresenterFn
+	OrderedResults bool
+	Prepared       *PreparedOperation
+	FieldResolver  FieldResolveFn
 }
 
 type executionContext struct {
@@ -110,20 +152,60 @@ type executionContext struct {
 	VariableValues map[string]interface{}
 	Errors         []gqlerrors.FormattedError
 	Context        context.Context
+	ErrorPresenter ErrorPresenterFn
+	OrderedResults bool
+	Prepared       *PreparedOperation
+	FieldResolver  FieldResolveFn
 }
 
 func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error) {
 	eCtx := &executionContext{}
+	eCtx.Prepared = p.Prepared
+
+	var (
+		operation *ast.OperationDefinition
+		fragments map[string]ast.Definition
+	)
+	if p.Prepared != nil {
+		operation, fragments = p.Prepared.operation, p.Prepared.fragments
+	} else {
+		var err error
+		operation, fragments, err = selectOperation(p.AST, p.OperationName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	variableValues, err := getVariableValues(p.Schema, operation.GetVariableDefinitions(), p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	eCtx.Schema = p.Schema
+	eCtx.Fragments = fragments
+	eCtx.Root = p.Root
+	eCtx.Operation = operation
+	eCtx.VariableValues = variableValues
+	eCtx.Context = p.Context
+	eCtx.ErrorPresenter = p.ErrorPresenter
+	eCtx.OrderedResults = p.OrderedResults
+	eCtx.FieldResolver = p.FieldResolver
+	return eCtx, nil
+}
+
+// selectOperation returns the operation of the document to execute, and the
+// fragments of the document.
+func selectOperation(AST *ast.Document, operationName string) (*ast.OperationDefinition, map[string]ast.Definition, error) {
 	var operation *ast.OperationDefinition
 	fragments := map[string]ast.Definition{}
 
-	for _, definition := range p.AST.Definitions {
+	for _, definition := range AST.Definitions {
 		switch definition := definition.(type) {
 		case *ast.OperationDefinition:
-			if (p.OperationName == "") && operation != nil {
-				return nil, errors.New("Must provide operation name if query contains multiple operations.")
+			if (operationName == "") && operation != nil {
+				return nil, nil, newOperationResolutionError("Must provide operation name if query contains multiple operations.", nil)
 			}
-			if p.OperationName == "" || definition.GetName() != nil && definition.GetName().Value == p.OperationName {
+			if operationName == "" || definition.GetName() != nil && definition.GetName().Value == operationName {
 				operation = definition
 			}
 		case *ast.FragmentDefinition:
@@ -133,29 +215,17 @@ func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error)
 			}
 			fragments[key] = definition
 		default:
-			return nil, fmt.Errorf("GraphQL cannot execute a request containing a %v", definition.GetKind())
+			return nil, nil, newOperationResolutionError(fmt.Sprintf("GraphQL cannot execute a request containing a %v", definition.GetKind()), nil)
 		}
 	}
 
 	if operation == nil {
-		if p.OperationName != "" {
-			return nil, fmt.Errorf(`Unknown operation named "%v".`, p.OperationName)
+		if operationName != "" {
+			return nil, nil, newOperationResolutionError(fmt.Sprintf(`Unknown operation named "%v".`, operationName), nil)
 		}
-		return nil, fmt.Errorf(`Must provide an operation.`)
+		return nil, nil, newOperationResolutionError(`Must provide an operation.`, nil)
 	}
-
-	variableValues, err := getVariableValues(p.Schema, operation.GetVariableDefinitions(), p.Args)
-	if err != nil {
-		return nil, err
-	}
-
-	eCtx.Schema = p.Schema
-	eCtx.Fragments = fragments
-	eCtx.Root = p.Root
-	eCtx.Operation = operation
-	eCtx.VariableValues = variableValues
-	eCtx.Context = p.Context
-	return eCtx, nil
+	return operation, fragments, nil
 }
 
 type executeOperationParams struct {
@@ -165,17 +235,11 @@ type executeOperationParams struct {
 }
 
 func executeOperation(p executeOperationParams) *Result {
-	operationType, err := getOperationRootType(p.ExecutionContext.Schema, p.Operation)
+	operationType, fields, err := operationRootFields(p.ExecutionContext, p.Operation)
 	if err != nil {
-		return &Result{Errors: gqlerrors.FormatErrors(err)}
+		return &Result{Errors: p.ExecutionContext.presentErrors(err)}
 	}
 
-	fields := collectFields(collectFieldsParams{
-		ExeContext:   p.ExecutionContext,
-		RuntimeType:  operationType,
-		SelectionSet: p.Operation.GetSelectionSet(),
-	})
-
 	executeFieldsParams := executeFieldsParams{
 		ExecutionContext: p.ExecutionContext,
 		ParentType:       operationType,
@@ -190,10 +254,28 @@ func executeOperation(p executeOperationParams) *Result {
 
 }
 
+// operationRootFields returns the root type of the operation and its root
+// fields, which are taken from the plan of the PreparedOperation if any.
+func operationRootFields(eCtx *executionContext, operation ast.Definition) (*Object, *collectedFields, error) {
+	if prepared := eCtx.Prepared; prepared != nil && prepared.rootFields != nil {
+		return prepared.rootType, prepared.rootFields, nil
+	}
+	operationType, err := getOperationRootType(eCtx.Schema, operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := collectFields(collectFieldsParams{
+		ExeContext:   eCtx,
+		RuntimeType:  operationType,
+		SelectionSet: operation.GetSelectionSet(),
+	})
+	return operationType, fields, nil
+}
+
 // Extracts the root type of the operation from the schema.
 func getOperationRootType(schema Schema, operation ast.Definition) (*Object, error) {
 	if operation == nil {
-		return nil, errors.New("Can only execute queries, mutations and subscription")
+		return nil, newOperationResolutionError("Can only execute queries, mutations and subscription", nil)
 	}
 
 	switch operation.GetOperation() {
@@ -202,46 +284,32 @@ func getOperationRootType(schema Schema, operation ast.Definition) (*Object, err
 	case ast.OperationTypeMutation:
 		mutationType := schema.MutationType()
 		if mutationType == nil || mutationType.PrivateName == "" {
-			return nil, gqlerrors.NewError(
-				"Schema is not configured for mutations",
-				[]ast.Node{operation},
-				"",
-				nil,
-				[]int{},
-				nil,
-			)
+			return nil, newOperationResolutionError("Schema is not configured for mutations", []ast.Node{operation})
 		}
 		return mutationType, nil
 	case ast.OperationTypeSubscription:
 		subscriptionType := schema.SubscriptionType()
 		if subscriptionType == nil || subscriptionType.PrivateName == "" {
-			return nil, gqlerrors.NewError(
-				"Schema is not configured for subscriptions",
-				[]ast.Node{operation},
-				"",
-				nil,
-				[]int{},
-				nil,
-			)
+			return nil, newOperationResolutionError("Schema is not configured for subscriptions", []ast.Node{operation})
 		}
 		return subscriptionType, nil
 	default:
-		return nil, gqlerrors.NewError(
-			"Can only execute queries, mutations and subscription",
-			[]ast.Node{operation},
-			"",
-			nil,
-			[]int{},
-			nil,
-		)
+		return nil, newOperationResolutionError("Can only execute queries, mutations and subscription", []ast.Node{operation})
 	}
 }
 
+// newOperationResolutionError creates an error for a request of which the
+// operation to execute cannot be determined.
+func newOperationResolutionError(message string, nodes []ast.Node) *gqlerrors.Error {
+	err := gqlerrors.NewError(message, nodes, "", nil, []int{}, nil)
+	return gqlerrors.SetCode(err, gqlerrors.ErrCodeOperationResolutionFailure)
+}
+
 type executeFieldsParams struct {
 	ExecutionContext *executionContext
 	ParentType       *Object
 	Source           interface{}
-	Fields           map[string][]*ast.Field
+	Fields           *collectedFields
 	Path             *ResponsePath
 }
 
@@ -251,24 +319,23 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 		p.Source = map[string]interface{}{}
 	}
 	if p.Fields == nil {
-		p.Fields = map[string][]*ast.Field{}
+		p.Fields = newCollectedFields()
 	}
 
-	finalResults := make(map[string]interface{}, len(p.Fields))
-	for _, orderedField := range orderedFields(p.Fields) {
-		responseName := orderedField.responseName
-		fieldASTs := orderedField.fieldASTs
+	finalResults := newResultObject(p.ExecutionContext, len(p.Fields.responseNames))
+	for _, responseName := range p.Fields.responseNames {
+		fieldASTs := p.Fields.fields[responseName]
 		fieldPath := p.Path.WithKey(responseName)
 		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
 		if state.hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		finalResults.set(responseName, resolved)
 	}
-	dethunkMapDepthFirst(finalResults)
+	dethunkMapDepthFirst(finalResults.value())
 
 	return &Result{
-		Data:   finalResults,
+		Data:   finalResults.value(),
 		Errors: p.ExecutionContext.Errors,
 	}
 }
@@ -285,26 +352,59 @@ func executeFields(p executeFieldsParams) *Result {
 	}
 }
 
-func executeSubFields(p executeFieldsParams) map[string]interface{} {
+// executeSubFields returns the results of the fields, as a
+// `map[string]interface{}`, or as an *OrderedMap when executing with
+// OrderedResults.
+func executeSubFields(p executeFieldsParams) interface{} {
 
 	if p.Source == nil {
 		p.Source = map[string]interface{}{}
 	}
 	if p.Fields == nil {
-		p.Fields = map[string][]*ast.Field{}
+		p.Fields = newCollectedFields()
 	}
 
-	finalResults := make(map[string]interface{}, len(p.Fields))
-	for responseName, fieldASTs := range p.Fields {
+	finalResults := newResultObject(p.ExecutionContext, len(p.Fields.responseNames))
+	for _, responseName := range p.Fields.responseNames {
+		fieldASTs := p.Fields.fields[responseName]
 		fieldPath := p.Path.WithKey(responseName)
 		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
 		if state.hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		finalResults.set(responseName, resolved)
+	}
+
+	return finalResults.value()
+}
+
+// resultObject builds the result of a selection set, either as a
+// `map[string]interface{}` or as an *OrderedMap.
+type resultObject struct {
+	unordered map[string]interface{}
+	ordered   *OrderedMap
+}
+
+func newResultObject(eCtx *executionContext, size int) resultObject {
+	if eCtx.OrderedResults {
+		return resultObject{ordered: newOrderedMap(size)}
 	}
+	return resultObject{unordered: make(map[string]interface{}, size)}
+}
 
-	return finalResults
+func (r resultObject) set(key string, value interface{}) {
+	if r.ordered != nil {
+		r.ordered.Set(key, value)
+		return
+	}
+	r.unordered[key] = value
+}
+
+func (r resultObject) value() interface{} {
+	if r.ordered != nil {
+		return r.ordered
+	}
+	return r.unordered
 }
 
 // dethunkQueue is a structure that allows us to execute a classic breadth-first traversal.
@@ -326,26 +426,39 @@ func (d *dethunkQueue) shift() func() {
 // in the map values and replacing each thunk with that thunk's return value. This parallels
 // the reference graphql-js implementation, which calls Promise.all on thunks at each depth (which
 // is an implicit parallel descent).
-func dethunkMapWithBreadthFirstTraversal(finalResults map[string]interface{}) {
+func dethunkMapWithBreadthFirstTraversal(finalResults interface{}) {
 	dethunkQueue := &dethunkQueue{DethunkFuncs: []func(){}}
-	dethunkMapBreadthFirst(finalResults, dethunkQueue)
+	dethunkValueBreadthFirst(finalResults, dethunkQueue)
 	for len(dethunkQueue.DethunkFuncs) > 0 {
 		f := dethunkQueue.shift()
 		f()
 	}
 }
 
+func dethunkValueBreadthFirst(v interface{}, dethunkQueue *dethunkQueue) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		dethunkMapBreadthFirst(val, dethunkQueue)
+	case *OrderedMap:
+		dethunkOrderedMapBreadthFirst(val, dethunkQueue)
+	}
+}
+
 func dethunkMapBreadthFirst(m map[string]interface{}, dethunkQueue *dethunkQueue) {
 	for k, v := range m {
 		if f, ok := v.(func() interface{}); ok {
 			m[k] = f()
 		}
-		switch val := m[k].(type) {
-		case map[string]interface{}:
-			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
-		case []interface{}:
-			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
+		pushDethunkBreadthFirst(m[k], dethunkQueue)
+	}
+}
+
+func dethunkOrderedMapBreadthFirst(m *OrderedMap, dethunkQueue *dethunkQueue) {
+	for _, k := range m.keys {
+		if f, ok := m.values[k].(func() interface{}); ok {
+			m.values[k] = f()
 		}
+		pushDethunkBreadthFirst(m.values[k], dethunkQueue)
 	}
 }
 
@@ -354,12 +467,18 @@ func dethunkListBreadthFirst(list []interface{}, dethunkQueue *dethunkQueue) {
 		if f, ok := v.(func() interface{}); ok {
 			list[i] = f()
 		}
-		switch val := list[i].(type) {
-		case map[string]interface{}:
-			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
-		case []interface{}:
-			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
-		}
+		pushDethunkBreadthFirst(list[i], dethunkQueue)
+	}
+}
+
+func pushDethunkBreadthFirst(v interface{}, dethunkQueue *dethunkQueue) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
+	case *OrderedMap:
+		dethunkQueue.push(func() { dethunkOrderedMapBreadthFirst(val, dethunkQueue) })
+	case []interface{}:
+		dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
 	}
 }
 
@@ -367,16 +486,21 @@ func dethunkListBreadthFirst(list []interface{}, dethunkQueue *dethunkQueue) {
 // in the map values and replacing each thunk with that thunk's return value. This is needed
 // to conform to the graphql-js reference implementation, which requires serial (depth-first)
 // implementations for mutation selects.
-func dethunkMapDepthFirst(m map[string]interface{}) {
-	for k, v := range m {
-		if f, ok := v.(func() interface{}); ok {
-			m[k] = f()
+func dethunkMapDepthFirst(v interface{}) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			if f, ok := v.(func() interface{}); ok {
+				m[k] = f()
+			}
+			dethunkValueDepthFirst(m[k])
 		}
-		switch val := m[k].(type) {
-		case map[string]interface{}:
-			dethunkMapDepthFirst(val)
-		case []interface{}:
-			dethunkListDepthFirst(val)
+	case *OrderedMap:
+		for _, k := range m.keys {
+			if f, ok := m.values[k].(func() interface{}); ok {
+				m.values[k] = f()
+			}
+			dethunkValueDepthFirst(m.values[k])
 		}
 	}
 }
@@ -386,12 +510,16 @@ func dethunkListDepthFirst(list []interface{}) {
 		if f, ok := v.(func() interface{}); ok {
 			list[i] = f()
 		}
-		switch val := list[i].(type) {
-		case map[string]interface{}:
-			dethunkMapDepthFirst(val)
-		case []interface{}:
-			dethunkListDepthFirst(val)
-		}
+		dethunkValueDepthFirst(list[i])
+	}
+}
+
+func dethunkValueDepthFirst(v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}, *OrderedMap:
+		dethunkMapDepthFirst(val)
+	case []interface{}:
+		dethunkListDepthFirst(val)
 	}
 }
 
@@ -399,23 +527,47 @@ type collectFieldsParams struct {
 	ExeContext           *executionContext
 	RuntimeType          *Object // previously known as OperationType
 	SelectionSet         *ast.SelectionSet
-	Fields               map[string][]*ast.Field
+	Fields               *collectedFields
 	VisitedFragmentNames map[string]bool
 }
 
+// collectedFields are the fields of a selection set grouped by response
+// name, with the response names in the order in which they first appear in
+// the selection set.
+type collectedFields struct {
+	responseNames []string
+	fields        map[string][]*ast.Field
+}
+
+func newCollectedFields() *collectedFields {
+	return &collectedFields{
+		fields: map[string][]*ast.Field{},
+	}
+}
+
+func (c *collectedFields) add(responseName string, field *ast.Field) {
+	if _, ok := c.fields[responseName]; !ok {
+		c.responseNames = append(c.responseNames, responseName)
+	}
+	c.fields[responseName] = append(c.fields[responseName], field)
+}
+
 // Given a selectionSet, adds all of the fields in that selection to
 // the passed in map of fields, and returns it at the end.
 // CollectFields requires the "runtime type" of an object. For a field which
 // returns and Interface or Union type, the "runtime type" will be the actual
 // Object type returned by that field.
-func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
+func collectFields(p collectFieldsParams) (fields *collectedFields) {
 	// overlying SelectionSet & Fields to fields
 	if p.SelectionSet == nil {
+		if p.Fields == nil {
+			return newCollectedFields()
+		}
 		return p.Fields
 	}
 	fields = p.Fields
 	if fields == nil {
-		fields = map[string][]*ast.Field{}
+		fields = newCollectedFields()
 	}
 	if p.VisitedFragmentNames == nil {
 		p.VisitedFragmentNames = map[string]bool{}
@@ -426,11 +578,7 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 			if !shouldIncludeNode(p.ExeContext, selection.Directives) {
 				continue
 			}
-			name := getFieldEntryKey(selection)
-			if _, ok := fields[name]; !ok {
-				fields[name] = []*ast.Field{}
-			}
-			fields[name] = append(fields[name], selection)
+			fields.add(getFieldEntryKey(selection), selection)
 		case *ast.InlineFragment:
 
 			if !shouldIncludeNode(p.ExeContext, selection.Directives) ||
@@ -581,12 +729,22 @@ type resolveFieldResultState struct {
 }
 
 func handleFieldError(r interface{}, fieldNodes []ast.Node, path *ResponsePath, returnType Output, eCtx *executionContext) {
-	err := NewLocatedErrorWithPath(r, fieldNodes, path.AsArray())
+	err := gqlerrors.SetCode(NewLocatedErrorWithPath(r, fieldNodes, path.AsArray()), gqlerrors.ErrCodeResolverError)
 	// send panic upstream
 	if _, ok := returnType.(*NonNull); ok {
 		panic(err)
 	}
-	eCtx.Errors = append(eCtx.Errors, gqlerrors.FormatError(err))
+	eCtx.Errors = append(eCtx.Errors, eCtx.presentError(err))
+}
+
+// presentError formats err with the ErrorPresenter of the execution.
+func (eCtx *executionContext) presentError(err error) gqlerrors.FormattedError {
+	return presentError(eCtx.Context, eCtx.ErrorPresenter, err)
+}
+
+// presentErrors formats each of the errs with the ErrorPresenter of the execution.
+func (eCtx *executionContext) presentErrors(errs ...error) []gqlerrors.FormattedError {
+	return presentErrors(eCtx.Context, eCtx.ErrorPresenter, errs...)
 }
 
 // Resolves the field on the given source object. In particular, this
@@ -604,33 +762,50 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 		return result, resultState
 	}()
 
-	fieldAST := fieldASTs[0]
-	fieldName := ""
-	if fieldAST.Name != nil {
-		fieldName = fieldAST.Name.Value
-	}
-
-	fieldDef := getFieldDef(eCtx.Schema, parentType, fieldName)
+	fieldDef := getFieldDef(eCtx.Schema, parentType, fieldASTName(fieldASTs[0]))
 	if fieldDef == nil {
 		resultState.hasNoFieldDefs = true
 		return nil, resultState
 	}
 	returnType = fieldDef.Type
+
+	info, result := resolveFieldValue(eCtx, fieldDef, parentType, source, fieldASTs, path)
+
+	completed := completeValueCatchingError(eCtx, returnType, fieldASTs, info, path, result)
+	return completed, resultState
+}
+
+// fieldASTName returns the name of the field, as opposed to its response name.
+func fieldASTName(fieldAST *ast.Field) string {
+	if fieldAST.Name != nil {
+		return fieldAST.Name.Value
+	}
+	return ""
+}
+
+// resolveFieldValue calls the resolver of the field, wrapped by the field
+// middleware and the extensions, and panics with the error it returns.
+func resolveFieldValue(eCtx *executionContext, fieldDef *FieldDefinition, parentType *Object, source interface{}, fieldASTs []*ast.Field, path *ResponsePath) (ResolveInfo, interface{}) {
+	fieldAST := fieldASTs[0]
 	resolveFn := fieldDef.Resolve
+	if resolveFn == nil {
+		resolveFn = eCtx.FieldResolver
+	}
 	if resolveFn == nil {
 		resolveFn = DefaultResolveFn
 	}
+	resolveFn = applyFieldMiddleware(eCtx.Schema, parentType, resolveFn)
 
 	// Build a map of arguments from the field.arguments AST, using the
 	// variables scope to fulfill any variable references.
 	// TODO: find a way to memoize, in case this field is within a List type.
-	args := getArgumentValues(fieldDef.Args, fieldAST.Arguments, eCtx.VariableValues)
+	args := getFieldArgumentValues(eCtx.Schema, fieldDef, fieldAST.Arguments, eCtx.VariableValues)
 
 	info := ResolveInfo{
-		FieldName:      fieldName,
+		FieldName:      fieldASTName(fieldAST),
 		FieldASTs:      fieldASTs,
 		Path:           path,
-		ReturnType:     returnType,
+		ReturnType:     fieldDef.Type,
 		ParentType:     parentType,
 		Schema:         eCtx.Schema,
 		Fragments:      eCtx.Fragments,
@@ -639,14 +814,12 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 		VariableValues: eCtx.VariableValues,
 	}
 
-	var resolveFnError error
-
 	extErrs, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(eCtx.Schema.extensions, eCtx, &info)
 	if len(extErrs) != 0 {
 		eCtx.Errors = append(eCtx.Errors, extErrs...)
 	}
 
-	result, resolveFnError = resolveFn(ResolveParams{
+	result, resolveFnError := resolveFn(ResolveParams{
 		Source:  source,
 		Args:    args,
 		Info:    info,
@@ -661,9 +834,7 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 	if resolveFnError != nil {
 		panic(resolveFnError)
 	}
-
-	completed := completeValueCatchingError(eCtx, returnType, fieldASTs, info, path, result)
-	return completed, resultState
+	return info, result
 }
 
 func completeValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) (completed interface{}) {
@@ -698,12 +869,7 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 	if returnType, ok := returnType.(*NonNull); ok {
 		completed := completeValue(eCtx, returnType.OfType, fieldASTs, info, path, result)
 		if completed == nil {
-			err := NewLocatedErrorWithPath(
-				fmt.Sprintf("Cannot return null for non-nullable field %v.%v.", info.ParentType, info.FieldName),
-				FieldASTsToNodeASTs(fieldASTs),
-				path.AsArray(),
-			)
-			panic(gqlerrors.FormatError(err))
+			panic(newNonNullError(fieldASTs, info, path))
 		}
 		return completed
 	}
@@ -751,6 +917,16 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 	return nil
 }
 
+// newNonNullError creates the error raised when a non-null field resolves to null.
+func newNonNullError(fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath) *gqlerrors.Error {
+	err := NewLocatedErrorWithPath(
+		fmt.Sprintf("Cannot return null for non-nullable field %v.%v.", info.ParentType, info.FieldName),
+		FieldASTsToNodeASTs(fieldASTs),
+		path.AsArray(),
+	)
+	return gqlerrors.SetCode(err, gqlerrors.ErrCodeNonNullViolation)
+}
+
 func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) (completed interface{}) {
 
 	// catch any panic invoked from the propertyFn (thunk)
@@ -760,6 +936,20 @@ func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fi
 		}
 	}()
 
+	result = resolveThunk(result)
+
+	if returnType, ok := returnType.(*NonNull); ok {
+		completed := completeValue(eCtx, returnType, fieldASTs, info, path, result)
+		return completed
+	}
+	completed = completeValue(eCtx, returnType, fieldASTs, info, path, result)
+
+	return completed
+}
+
+// resolveThunk calls the thunk returned by a resolver, and panics with the
+// error it returns.
+func resolveThunk(result interface{}) interface{} {
 	propertyFn, ok := result.(func() (interface{}, error))
 	if !ok {
 		err := gqlerrors.NewFormattedError("Error resolving func. Expected `func() (interface{}, error)` signature")
@@ -769,22 +959,19 @@ func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fi
 	if err != nil {
 		panic(gqlerrors.FormatError(err))
 	}
-
-	result = fnResult
-
-	if returnType, ok := returnType.(*NonNull); ok {
-		completed := completeValue(eCtx, returnType, fieldASTs, info, path, result)
-		return completed
-	}
-	completed = completeValue(eCtx, returnType, fieldASTs, info, path, result)
-
-	return completed
+	return fnResult
 }
 
 // completeAbstractValue completes value of an Abstract type (Union / Interface) by determining the runtime type
 // of that value, then completing based on that type.
 func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) interface{} {
+	runtimeType := resolveRuntimeType(eCtx, returnType, info, result)
+	return completeObjectValue(eCtx, runtimeType, fieldASTs, info, path, result)
+}
 
+// resolveRuntimeType determines the runtime Object type of a value of an
+// Abstract type, and panics if it is not a possible type.
+func resolveRuntimeType(eCtx *executionContext, returnType Abstract, info ResolveInfo, result interface{}) *Object {
 	var runtimeType *Object
 
 	resolveTypeParams := ResolveTypeParams{
@@ -796,6 +983,10 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 		runtimeType = unionReturnType.ResolveType(resolveTypeParams)
 	} else if interfaceReturnType, ok := returnType.(*Interface); ok && interfaceReturnType.ResolveType != nil {
 		runtimeType = interfaceReturnType.ResolveType(resolveTypeParams)
+	} else if unionReturnType, ok := returnType.(*Union); ok && unionReturnType.ResolveTypeName != nil {
+		runtimeType, _ = eCtx.Schema.Type(unionReturnType.ResolveTypeName(resolveTypeParams)).(*Object)
+	} else if interfaceReturnType, ok := returnType.(*Interface); ok && interfaceReturnType.ResolveTypeName != nil {
+		runtimeType, _ = eCtx.Schema.Type(interfaceReturnType.ResolveTypeName(resolveTypeParams)).(*Object)
 	} else {
 		runtimeType = defaultResolveTypeFn(resolveTypeParams, returnType)
 	}
@@ -813,13 +1004,24 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 				`for "%v".`, runtimeType, returnType),
 		))
 	}
-
-	return completeObjectValue(eCtx, runtimeType, fieldASTs, info, path, result)
+	return runtimeType
 }
 
 // completeObjectValue complete an Object value by executing all sub-selections.
 func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) interface{} {
+	executeFieldsParams := executeFieldsParams{
+		ExecutionContext: eCtx,
+		ParentType:       returnType,
+		Source:           result,
+		Fields:           collectSubFields(eCtx, returnType, fieldASTs, info, result),
+		Path:             path,
+	}
+	return executeSubFields(executeFieldsParams)
+}
 
+// collectSubFields checks that the value is of the Object type, and collects
+// the sub-fields to execute to complete it.
+func collectSubFields(eCtx *executionContext, returnType *Object, fieldASTs []*ast.Field, info ResolveInfo, result interface{}) *collectedFields {
 	// If there is an isTypeOf predicate function, call it with the
 	// current result. If isTypeOf returns false, then raise an error rather
 	// than continuing execution.
@@ -837,7 +1039,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 	}
 
 	// Collect sub-fields to execute to complete this value.
-	subFieldASTs := map[string][]*ast.Field{}
+	subFieldASTs := newCollectedFields()
 	visitedFragmentNames := map[string]bool{}
 	for _, fieldAST := range fieldASTs {
 		if fieldAST == nil {
@@ -855,19 +1057,21 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 			subFieldASTs = collectFields(innerParams)
 		}
 	}
-	executeFieldsParams := executeFieldsParams{
-		ExecutionContext: eCtx,
-		ParentType:       returnType,
-		Source:           result,
-		Fields:           subFieldASTs,
-		Path:             path,
-	}
-	return executeSubFields(executeFieldsParams)
+	return subFieldASTs
 }
 
 // completeLeafValue complete a leaf value (Scalar / Enum) by serializing to a valid value, returning nil if serialization is not possible.
+// It panics with the error of the serialization of scalars returning one.
 func completeLeafValue(returnType Leaf, result interface{}) interface{} {
-	serializedResult := returnType.Serialize(result)
+	var serializedResult interface{}
+	if scalar, ok := returnType.(*Scalar); ok {
+		var err error
+		if serializedResult, err = scalar.SerializeWithError(result); err != nil {
+			panic(err)
+		}
+	} else {
+		serializedResult = returnType.Serialize(result)
+	}
 	if isNullish(serializedResult) {
 		return nil
 	}
@@ -876,6 +1080,21 @@ func completeLeafValue(returnType Leaf, result interface{}) interface{} {
 
 // completeListValue complete a list value by completing each item in the list with the inner type
 func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) interface{} {
+	resultVal := listValue(info, result)
+	itemType := returnType.OfType
+	completedResults := make([]interface{}, 0, resultVal.Len())
+	for i := 0; i < resultVal.Len(); i++ {
+		val := resultVal.Index(i).Interface()
+		fieldPath := path.WithKey(i)
+		completedItem := completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
+		completedResults = append(completedResults, completedItem)
+	}
+	return completedResults
+}
+
+// listValue returns the reflected value of a list, and panics if the value
+// is not iterable.
+func listValue(info ResolveInfo, result interface{}) reflect.Value {
 	resultVal := reflect.ValueOf(result)
 	if resultVal.Kind() == reflect.Ptr {
 		resultVal = resultVal.Elem()
@@ -892,22 +1111,29 @@ func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*as
 	if err != nil {
 		panic(gqlerrors.FormatError(err))
 	}
-
-	itemType := returnType.OfType
-	completedResults := make([]interface{}, 0, resultVal.Len())
-	for i := 0; i < resultVal.Len(); i++ {
-		val := resultVal.Index(i).Interface()
-		fieldPath := path.WithKey(i)
-		completedItem := completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
-		completedResults = append(completedResults, completedItem)
-	}
-	return completedResults
+	return resultVal
 }
 
 // defaultResolveTypeFn If a resolveType function is not given, then a default resolve behavior is
-// used which tests each possible type for the abstract type by calling
-// isTypeOf for the object being coerced, returning the first type that matches.
+// used which looks the Go type of the value up in the GoTypes of the schema,
+// then uses the `__typename` key of map values, and otherwise tests each
+// possible type for the abstract type by calling isTypeOf for the object
+// being coerced, returning the first type that matches.
 func defaultResolveTypeFn(p ResolveTypeParams, abstractType Abstract) *Object {
+	// Look the Go type of the value up in the registry of the schema.
+	if ttype := p.Info.Schema.GoTypeObject(p.Value); ttype != nil && p.Info.Schema.IsPossibleType(abstractType, ttype) {
+		return ttype
+	}
+
+	// Use the `__typename` of map values.
+	if value, ok := p.Value.(map[string]interface{}); ok {
+		if typeName, ok := value[TypeNameMetaFieldDef.Name].(string); ok {
+			if ttype, ok := p.Info.Schema.Type(typeName).(*Object); ok && p.Info.Schema.IsPossibleType(abstractType, ttype) {
+				return ttype
+			}
+		}
+	}
+
 	possibleTypes := p.Info.Schema.PossibleTypes(abstractType)
 	for _, possibleType := range possibleTypes {
 		if possibleType.IsTypeOf == nil {
@@ -1039,39 +1265,3 @@ func getFieldDef(schema Schema, parentType *Object, fieldName string) *FieldDefi
 	}
 	return parentType.Fields()[fieldName]
 }
-
-// contains field information that will be placed in an ordered slice
-type orderedField struct {
-	responseName string
-	fieldASTs    []*ast.Field
-}
-
-// orders fields from a fields map by location in the source
-func orderedFields(fields map[string][]*ast.Field) []*orderedField {
-	orderedFields := []*orderedField{}
-	fieldMap := map[int]*orderedField{}
-	startLocs := []int{}
-
-	for responseName, fieldASTs := range fields {
-		// find the lowest location in the current fieldASTs
-		lowest := -1
-		for _, fieldAST := range fieldASTs {
-			loc := fieldAST.GetLoc().Start
-			if lowest == -1 || loc < lowest {
-				lowest = loc
-			}
-		}
-		startLocs = append(startLocs, lowest)
-		fieldMap[lowest] = &orderedField{
-			responseName: responseName,
-			fieldASTs:    fieldASTs,
-		}
-	}
-
-	sort.Ints(startLocs)
-	for _, startLoc := range startLocs {
-		orderedFields = append(orderedFields, fieldMap[startLoc])
-	}
-
-	return orderedFields
-}
@@ -581,6 +581,7 @@ func TestNullsOutErrorSubtrees(t *testing.T) {
 		Path: []interface{}{
 			"syncError",
 		},
+		Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 	},
 	}
 
@@ -771,8 +772,9 @@ func TestThrowsIfNoOperationIsProvided(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   "Must provide an operation.",
-			Locations: []location.SourceLocation{},
+			Message:    "Must provide an operation.",
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 		},
 	}
 
@@ -816,8 +818,9 @@ func TestThrowsIfNoOperationNameIsProvidedWithMultipleOperations(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   "Must provide operation name if query contains multiple operations.",
-			Locations: []location.SourceLocation{},
+			Message:    "Must provide operation name if query contains multiple operations.",
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 		},
 	}
 
@@ -862,8 +865,9 @@ func TestThrowsIfUnknownOperationNameIsProvided(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   `Unknown operation named "UnknownExample".`,
-			Locations: []location.SourceLocation{},
+			Message:    `Unknown operation named "UnknownExample".`,
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 		},
 	}
 
@@ -906,12 +910,14 @@ func TestThrowsIfOperationTypeIsUnsupported(t *testing.T) {
 
 	expectedErrors := [][]gqlerrors.FormattedError{
 		{{
-			Message:   `Schema is not configured for mutations`,
-			Locations: []location.SourceLocation{{Line: 1, Column: 1}},
+			Message:    `Schema is not configured for mutations`,
+			Locations:  []location.SourceLocation{{Line: 1, Column: 1}},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 		}},
 		{{
-			Message:   `Schema is not configured for subscriptions`,
-			Locations: []location.SourceLocation{{Line: 1, Column: 20}},
+			Message:    `Schema is not configured for subscriptions`,
+			Locations:  []location.SourceLocation{{Line: 1, Column: 20}},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 		}},
 	}
 
@@ -1414,6 +1420,7 @@ func TestFailsWhenAnIsTypeOfCheckIsNotMet(t *testing.T) {
 				"specials",
 				1,
 			},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 		},
 		},
 	}
@@ -1478,8 +1485,9 @@ func TestFailsToExecuteQueryContainingATypeDefinition(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message:   "GraphQL cannot execute a request containing a ObjectDefinition",
-				Locations: []location.SourceLocation{},
+				Message:    "GraphQL cannot execute a request containing a ObjectDefinition",
+				Locations:  []location.SourceLocation{},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeOperationResolutionFailure},
 			},
 		},
 	}
@@ -1862,8 +1870,9 @@ func TestContextDeadline(t *testing.T) {
 	acceptableDelay := time.Millisecond * time.Duration(10)
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   context.DeadlineExceeded.Error(),
-			Locations: []location.SourceLocation{},
+			Message:    context.DeadlineExceeded.Error(),
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeExecutionCanceled},
 		},
 	}
 
@@ -2235,7 +2244,8 @@ func TestQuery_ErrorPath(t *testing.T) {
 		{
 		  "message": "Name for character with ID 1002 could not be fetched.",
 		  "locations": [ { "line": 6, "column": 7 } ],
-		  "path": [ "hero", "heroFriends", 1, "name" ]
+		  "path": [ "hero", "heroFriends", 1, "name" ],
+		  "extensions": { "code": "RESOLVER_ERROR" }
 		}
 	  ],
 	  "data": {
@@ -2269,7 +2279,8 @@ func TestQuery_ErrorPathForNonNullField(t *testing.T) {
 		{
 		  "message": "Name for character with ID 1002 could not be fetched.",
 		  "locations": [ { "line": 6, "column": 7 } ],
-		  "path": [ "hero", "heroFriends", 1, "name" ]
+		  "path": [ "hero", "heroFriends", 1, "name" ],
+		  "extensions": { "code": "RESOLVER_ERROR" }
 		}
 	  ],
 	  "data": {
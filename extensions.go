@@ -27,6 +27,11 @@ type (
 	ResolveFieldFinishFunc func(interface{}, error)
 	// resolveFieldFinishFuncHandler calls the resolveFieldFinishFns for all the extensions
 	resolveFieldFinishFuncHandler func(interface{}, error) []gqlerrors.FormattedError
+
+	// SubscriptionFinishFunc is called when the subscription ends, with the error ending it if any
+	SubscriptionFinishFunc func(error)
+	// subscriptionFinishFuncHandler calls the SubscriptionFinishFuncs of the SubscriptionExtensions
+	subscriptionFinishFuncHandler func(error) []gqlerrors.FormattedError
 )
 
 // Extension is an interface for extensions in graphql
@@ -56,6 +61,15 @@ type Extension interface {
 	GetResult(context.Context) interface{}
 }
 
+// SubscriptionExtension is an Extension notified about the start and the end of the subscriptions,
+// whose events are executed as queries, each notified by ExecutionDidStart
+type SubscriptionExtension interface {
+	Extension
+
+	// SubscriptionDidStart is called before the source stream of a subscription is created
+	SubscriptionDidStart(context.Context) (context.Context, SubscriptionFinishFunc)
+}
+
 // handleExtensionsInits handles all the init functions for all the extensions in the schema
 func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 	errs := gqlerrors.FormattedErrors{}
@@ -64,7 +78,7 @@ func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 			// catch panic from an extension init fn
 			defer func() {
 				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), r.(error))))
+					errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.Init: %v", ext.Name(), r.(error))))
 				}
 			}()
 			// update context
@@ -87,7 +101,7 @@ func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parse
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), r.(error))))
+					errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
 			ctx, finishFn = ext.ParseDidStart(p.Context)
@@ -103,7 +117,7 @@ func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parse
 				// catch panic from a finishFn
 				defer func() {
 					if r := recover(); r != nil {
-						errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", name, r.(error))))
+						errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ParseFinishFunc: %v", name, r.(error))))
 					}
 				}()
 				fn(err)
@@ -126,7 +140,7 @@ func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError,
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), r.(error))))
+					errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
 			ctx, finishFn = ext.ValidationDidStart(p.Context)
@@ -142,7 +156,7 @@ func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError,
 				// catch panic from a finishFn
 				defer func() {
 					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", name, r.(error))))
+						extErrs = append(extErrs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ValidationFinishFunc: %v", name, r.(error))))
 					}
 				}()
 				finishFn(errs)
@@ -165,7 +179,7 @@ func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedE
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), r.(error))))
+					errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
 			ctx, finishFn = ext.ExecutionDidStart(p.Context)
@@ -181,7 +195,7 @@ func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedE
 				// catch panic from a finishFn
 				defer func() {
 					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ExecutionFinishFunc: %v", name, r.(error))))
+						extErrs = append(extErrs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.ExecutionFinishFunc: %v", name, r.(error))))
 					}
 				}()
 				finishFn(result)
@@ -204,7 +218,7 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), r.(error))))
+					errs = append(errs, extensionError(p.Context, p.ErrorPresenter, fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
 			ctx, finishFn = ext.ResolveFieldDidStart(p.Context, i)
@@ -220,7 +234,7 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 				// catch panic from a finishFn
 				defer func() {
 					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", name, r.(error))))
+						extErrs = append(extErrs, extensionError(p.Context, p.ErrorPresenter, fmt.Errorf("%s.ResolveFieldFinishFunc: %v", name, r.(error))))
 					}
 				}()
 				finishFn(val, err)
@@ -230,13 +244,56 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 	}
 }
 
+// handleExtensionsSubscriptionDidStart notifies the SubscriptionExtensions about the start of a subscription
+func handleExtensionsSubscriptionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedError, subscriptionFinishFuncHandler) {
+	fs := map[string]SubscriptionFinishFunc{}
+	errs := gqlerrors.FormattedErrors{}
+	for _, ext := range p.Schema.extensions {
+		ext, ok := ext.(SubscriptionExtension)
+		if !ok {
+			continue
+		}
+		var (
+			ctx      context.Context
+			finishFn SubscriptionFinishFunc
+		)
+		// catch panic from an extension's subscriptionDidStart function
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errs = append(errs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.SubscriptionDidStart: %v", ext.Name(), r.(error))))
+				}
+			}()
+			ctx, finishFn = ext.SubscriptionDidStart(p.Context)
+			// update context
+			p.Context = ctx
+			fs[ext.Name()] = finishFn
+		}()
+	}
+	return errs, func(err error) []gqlerrors.FormattedError {
+		extErrs := gqlerrors.FormattedErrors{}
+		for name, finishFn := range fs {
+			func() {
+				// catch panic from a finishFn
+				defer func() {
+					if r := recover(); r != nil {
+						extErrs = append(extErrs, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.SubscriptionFinishFunc: %v", name, r.(error))))
+					}
+				}()
+				finishFn(err)
+			}()
+		}
+		return extErrs
+	}
+}
+
 func addExtensionResults(p *ExecuteParams, result *Result) {
 	if len(p.Schema.extensions) != 0 {
 		for _, ext := range p.Schema.extensions {
 			func() {
 				defer func() {
 					if r := recover(); r != nil {
-						result.Errors = append(result.Errors, gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), r.(error))))
+						result.Errors = append(result.Errors, extensionError(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), fmt.Errorf("%s.GetResult: %v", ext.Name(), r.(error))))
 					}
 				}()
 				if ext.HasResult() {
@@ -249,3 +306,9 @@ func addExtensionResults(p *ExecuteParams, result *Result) {
 		}
 	}
 }
+
+// extensionError formats an error raised by an extension with the presenter,
+// marking it as an internal server error.
+func extensionError(ctx context.Context, presenter ErrorPresenterFn, err error) gqlerrors.FormattedError {
+	return presentError(ctx, presenter, newCodedError(err, gqlerrors.ErrCodeInternalServerError))
+}
@@ -59,7 +59,7 @@ func TestExtensionInitPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -90,7 +90,7 @@ func TestExtensionParseDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -118,7 +118,7 @@ func TestExtensionParseFinishFuncPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ParseFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -149,7 +149,7 @@ func TestExtensionValidationDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -177,7 +177,7 @@ func TestExtensionValidationFinishFuncPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ValidationFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -208,7 +208,7 @@ func TestExtensionExecutionDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -238,7 +238,7 @@ func TestExtensionExecutionFinishFuncPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ExecutionFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 
@@ -272,7 +272,7 @@ func TestExtensionResolveFieldDidStartPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 
@@ -281,6 +281,35 @@ func TestExtensionResolveFieldDidStartPanic(t *testing.T) {
 	}
 }
 
+func TestExtensionErrorsArePresented(t *testing.T) {
+	ext := newtestExt("testExt")
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		panic(errors.New("internal error"))
+	}
+	ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+		panic(errors.New("internal error"))
+	}
+
+	schema := tinit(t)
+	schema.AddExtensions(ext)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query Example { a }`,
+		ErrorPresenter: func(ctx context.Context, err error) gqlerrors.FormattedError {
+			return gqlerrors.FormattedError{Message: "masked"}
+		},
+	})
+	if len(result.Errors) == 0 {
+		t.Fatalf("Expected the errors of the extension")
+	}
+	for _, err := range result.Errors {
+		if err.Message != "masked" {
+			t.Fatalf("Expected the errors of the extension to be presented, got %v", result.Errors)
+		}
+	}
+}
+
 func TestExtensionResolveFieldFinishFuncPanic(t *testing.T) {
 	ext := newtestExt("testExt")
 	ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
@@ -303,7 +332,7 @@ func TestExtensionResolveFieldFinishFuncPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
 
@@ -367,7 +396,7 @@ func TestExtensionGetResultPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), errors.New("test error"))),
+			extensionPanicError(fmt.Errorf("%s.GetResult: %v", ext.Name(), errors.New("test error"))),
 		},
 		Extensions: make(map[string]interface{}),
 	}
@@ -377,6 +406,144 @@ func TestExtensionGetResultPanic(t *testing.T) {
 	}
 }
 
+func TestExtensionSubscriptionLifecycle(t *testing.T) {
+	var calls []string
+	ext := &testSubscriptionExt{testExt: newtestExt("testExt")}
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		calls = append(calls, "Init")
+		return ctx
+	}
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		calls = append(calls, "ParseDidStart")
+		return ctx, func(err error) {}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		calls = append(calls, "ValidationDidStart")
+		return ctx, func([]gqlerrors.FormattedError) {}
+	}
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		calls = append(calls, "ExecutionDidStart")
+		return ctx, func(r *graphql.Result) {
+			calls = append(calls, "ExecutionFinishFunc")
+		}
+	}
+	ext.subscriptionDidStartFn = func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+		calls = append(calls, "SubscriptionDidStart")
+		return ctx, func(err error) {
+			calls = append(calls, fmt.Sprintf("SubscriptionFinishFunc: %v", err))
+		}
+	}
+	ext.hasResultFn = func() bool {
+		return true
+	}
+	ext.getResultFn = func(context.Context) interface{} {
+		return "result"
+	}
+
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+				Subscribe: makeSubscribeToStringFunction([]string{"a", "b"}),
+			},
+			"erred": &graphql.Field{
+				Type: graphql.String,
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("ooops")
+				},
+			},
+		},
+	})
+	schema.AddExtensions(ext)
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+	expectedResults := []*graphql.Result{
+		{Data: map[string]interface{}{"sub": "a"}, Extensions: map[string]interface{}{"testExt": "result"}},
+		{Data: map[string]interface{}{"sub": "b"}, Extensions: map[string]interface{}{"testExt": "result"}},
+	}
+	if !reflect.DeepEqual(expectedResults, results) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expectedResults, results))
+	}
+	expectedCalls := []string{
+		"Init",
+		"ParseDidStart",
+		"ValidationDidStart",
+		"SubscriptionDidStart",
+		"ExecutionDidStart",
+		"ExecutionFinishFunc",
+		"ExecutionDidStart",
+		"ExecutionFinishFunc",
+		"SubscriptionFinishFunc: <nil>",
+	}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+
+	calls = nil
+	for range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { erred }`,
+	}) {
+	}
+	expectedCalls = []string{
+		"Init",
+		"ParseDidStart",
+		"ValidationDidStart",
+		"SubscriptionDidStart",
+		"SubscriptionFinishFunc: ooops",
+	}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+}
+
+func TestExtensionSubscriptionDidStartPanic(t *testing.T) {
+	ext := &testSubscriptionExt{testExt: newtestExt("testExt")}
+	ext.subscriptionDidStartFn = func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+		if true {
+			panic(errors.New("test error"))
+		}
+		return ctx, func(err error) {}
+	}
+
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type:      graphql.String,
+				Subscribe: makeSubscribeToStringFunction([]string{"a"}),
+			},
+		},
+	})
+	schema.AddExtensions(ext)
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+	expected := []*graphql.Result{{
+		Errors: []gqlerrors.FormattedError{
+			extensionPanicError(fmt.Errorf("%s.SubscriptionDidStart: %v", ext.Name(), errors.New("test error"))),
+		},
+	}}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
 func newtestExt(name string) *testExt {
 	ext := &testExt{
 		name: name,
@@ -469,3 +636,19 @@ func (t *testExt) ExecutionDidStart(ctx context.Context) (context.Context, graph
 func (t *testExt) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
 	return t.resolveFieldDidStartFn(ctx, i)
 }
+
+type testSubscriptionExt struct {
+	*testExt
+	subscriptionDidStartFn func(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc)
+}
+
+func (t *testSubscriptionExt) SubscriptionDidStart(ctx context.Context) (context.Context, graphql.SubscriptionFinishFunc) {
+	return t.subscriptionDidStartFn(ctx)
+}
+
+func extensionPanicError(err error) gqlerrors.FormattedError {
+	return gqlerrors.FormatError(gqlerrors.SetCode(
+		gqlerrors.NewError(err.Error(), nil, "", nil, []int{}, err),
+		gqlerrors.ErrCodeInternalServerError,
+	))
+}
@@ -0,0 +1,115 @@
+package gqlerrors
+
+// Machine-readable error codes, set as the `code` extension of the errors
+// produced by the library, so that clients can classify errors without
+// matching their messages.
+const (
+	// ErrCodeParseFailed is set on errors raised while parsing the request.
+	ErrCodeParseFailed = "GRAPHQL_PARSE_FAILED"
+
+	// ErrCodeValidationFailed is set on errors reported by validation rules.
+	// Those errors also carry the name of the failed rule as the `rule`
+	// extension.
+	ErrCodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+
+	// ErrCodeBadUserInput is set on errors raised while coercing the
+	// variables of the request.
+	ErrCodeBadUserInput = "BAD_USER_INPUT"
+
+	// ErrCodeOperationResolutionFailure is set when the operation to execute
+	// cannot be determined, e.g. for an unknown operation name.
+	ErrCodeOperationResolutionFailure = "OPERATION_RESOLUTION_FAILURE"
+
+	// ErrCodeNonNullViolation is set when a non-null field resolved to null.
+	ErrCodeNonNullViolation = "NON_NULL_VIOLATION"
+
+	// ErrCodeResolverError is set on errors raised while resolving a field.
+	ErrCodeResolverError = "RESOLVER_ERROR"
+
+	// ErrCodeExecutionCanceled is set when the context of the request is done
+	// before the execution finished.
+	ErrCodeExecutionCanceled = "EXECUTION_CANCELED"
+
+	// ErrCodeOperationNotAllowed is set when the type of the operation is not
+	// one of the AllowedOperationTypes of the request, e.g. a mutation sent
+	// by a GET request.
+	ErrCodeOperationNotAllowed = "OPERATION_NOT_ALLOWED"
+
+	// ErrCodeInternalServerError is set on unexpected errors, e.g. panics
+	// raised by extensions.
+	ErrCodeInternalServerError = "INTERNAL_SERVER_ERROR"
+
+	// ErrCodePersistedQueryNotFound is set when the hash of a persisted query
+	// is unknown, in which case the client should send the query along with
+	// its hash.
+	ErrCodePersistedQueryNotFound = "PERSISTED_QUERY_NOT_FOUND"
+
+	// ErrCodePersistedQueryNotSupported is set when a request uses persisted
+	// queries but no store is configured, or an unsupported version.
+	ErrCodePersistedQueryNotSupported = "PERSISTED_QUERY_NOT_SUPPORTED"
+
+	// ErrCodePersistedQueryHashMismatch is set when the query sent along with
+	// a hash does not match it.
+	ErrCodePersistedQueryHashMismatch = "PERSISTED_QUERY_HASH_MISMATCH"
+
+	// ErrCodeDocumentNotTrusted is set when a schema only executes trusted
+	// documents and the request is not one of them.
+	ErrCodeDocumentNotTrusted = "DOCUMENT_NOT_TRUSTED"
+
+	// ErrCodeTrustedDocumentNotFound is set when the document ID of a request
+	// is not the one of a trusted document.
+	ErrCodeTrustedDocumentNotFound = "TRUSTED_DOCUMENT_NOT_FOUND"
+
+	// ErrCodeBatchLimitExceeded is set on the results of a batch of
+	// operations larger than the MaxBatchSize of the schema.
+	ErrCodeBatchLimitExceeded = "BATCH_LIMIT_EXCEEDED"
+
+	// ErrCodeBadRequest is set on the errors of HTTP requests which can not
+	// be executed, e.g. for a missing query or an unsupported method.
+	ErrCodeBadRequest = "BAD_REQUEST"
+)
+
+// SetCode returns the error with the `code` extension, unless it already has
+// one, see SetExtension.
+func SetCode(err *Error, code string) *Error {
+	return SetExtension(err, "code", code)
+}
+
+// SetExtension returns the error with the extension under the given key,
+// unless it is already set. The error is left unchanged, as it can be shared,
+// e.g. a sentinel error returned by the resolvers of concurrent requests, and
+// a copy of it and of its extensions is returned instead.
+func SetExtension(err *Error, key string, value interface{}) *Error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.Extensions[key]; ok {
+		return err
+	}
+	e := *err
+	e.Extensions = make(map[string]interface{}, len(err.Extensions)+1)
+	for k, v := range err.Extensions {
+		e.Extensions[k] = v
+	}
+	e.Extensions[key] = value
+	return &e
+}
+
+// Code returns the `code` extension of the error, if any.
+func Code(err error) string {
+	var extensions map[string]interface{}
+	switch err := err.(type) {
+	case FormattedError:
+		extensions = err.Extensions
+	case *FormattedError:
+		extensions = err.Extensions
+	case *Error:
+		extensions = FormatError(err).Extensions
+	case Error:
+		extensions = FormatError(&err).Extensions
+	case ExtendedError:
+		extensions = err.Extensions()
+	}
+	code, _ := extensions["code"].(string)
+	return code
+}
@@ -18,6 +18,11 @@ type Error struct {
 	Locations     []location.SourceLocation
 	OriginalError error
 	Path          []interface{}
+
+	// Extensions are added to the extensions of the formatted error, e.g.
+	// the `code` of the error. Extensions provided by an OriginalError that
+	// implements ExtendedError take precedence.
+	Extensions map[string]interface{}
 }
 
 // implements Golang's built-in `error` interface
@@ -25,6 +30,12 @@ func (g Error) Error() string {
 	return fmt.Sprintf("%v", g.Message)
 }
 
+// Unwrap returns the original error, so that errors.Is and errors.As can
+// inspect the error that caused this one.
+func (g Error) Unwrap() error {
+	return g.OriginalError
+}
+
 func NewError(message string, nodes []ast.Node, stack string, source *source.Source, positions []int, origError error) *Error {
 	return newError(message, nodes, stack, source, positions, nil, origError)
 }
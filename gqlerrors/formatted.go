@@ -27,6 +27,12 @@ func (g FormattedError) Error() string {
 	return g.Message
 }
 
+// Unwrap returns the original error, so that errors.Is and errors.As can
+// inspect the error that caused this one.
+func (g FormattedError) Unwrap() error {
+	return g.originalError
+}
+
 func NewFormattedError(message string) FormattedError {
 	err := errors.New(message)
 	return FormatError(err)
@@ -43,11 +49,13 @@ func FormatError(err error) FormattedError {
 			Path:          err.Path,
 			originalError: err,
 		}
+		var extended map[string]interface{}
 		if err := err.OriginalError; err != nil {
-			if extended, ok := err.(ExtendedError); ok {
-				ret.Extensions = extended.Extensions()
+			if err, ok := err.(ExtendedError); ok {
+				extended = err.Extensions()
 			}
 		}
+		ret.Extensions = mergeExtensions(err.Extensions, extended)
 		return ret
 	case Error:
 		return FormatError(&err)
@@ -60,6 +68,22 @@ func FormatError(err error) FormattedError {
 	}
 }
 
+// mergeExtensions merges the extensions of the error with the extensions of its
+// original error, the latter taking precedence.
+func mergeExtensions(extensions, extended map[string]interface{}) map[string]interface{} {
+	if len(extensions) == 0 {
+		return extended
+	}
+	merged := make(map[string]interface{}, len(extensions)+len(extended))
+	for key, value := range extensions {
+		merged[key] = value
+	}
+	for key, value := range extended {
+		merged[key] = value
+	}
+	return merged
+}
+
 func FormatErrors(errs ...error) []FormattedError {
 	formattedErrors := []FormattedError{}
 	for _, err := range errs {
@@ -2,8 +2,10 @@ package graphql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 )
@@ -20,7 +22,10 @@ type Params struct {
 	RootObject map[string]interface{}
 
 	// A mapping of variable name to runtime value to use for all variables
-	// defined in the requestString.
+	// defined in the requestString. Variables decoded from JSON are best
+	// decoded with the UseNumber method of json.Decoder, or of
+	// RequestDecoder, so that large integers keep their precision: the Int,
+	// Float and ID scalars accept json.Number values.
 	VariableValues map[string]interface{}
 
 	// The name of the operation to use if requestString contains multiple
@@ -31,38 +36,128 @@ type Params struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// ErrorPresenter may be provided to override the ErrorPresenter of the
+	// schema for this request.
+	ErrorPresenter ErrorPresenterFn
+
+	// OrderedResults, if set, makes the objects of the Result data
+	// *OrderedMap values, which keep the fields in the order of the
+	// selection set, instead of `map[string]interface{}` values.
+	OrderedResults bool
+
+	// DocumentCache may be provided to override the DocumentCache of the
+	// schema for this request.
+	DocumentCache *DocumentCache
+
+	// Extensions are the extensions of the request, e.g. the
+	// `persistedQuery` extension of the automatic persisted queries protocol.
+	Extensions map[string]interface{}
+
+	// PersistedQueryStore may be provided to override the PersistedQueryStore
+	// of the schema for this request.
+	PersistedQueryStore PersistedQueryStore
+
+	// DocumentID is the ID of the trusted document to execute, when the
+	// schema has TrustedDocuments. RequestString is then ignored.
+	DocumentID string
+
+	// AllowedOperationTypes, if set, restricts the operations which can be
+	// executed to the ones of those types, e.g. ast.OperationTypeQuery for
+	// the requests sent by GET. The type is checked once the persisted query
+	// or trusted document of the request is resolved, and other operations
+	// fail with an OPERATION_NOT_ALLOWED error.
+	AllowedOperationTypes []string
 }
 
 func Do(p Params) *Result {
+	AST, result := parseAndValidate(&p)
+	if result != nil {
+		return result
+	}
+	return Execute(executeParams(p, AST))
+}
+
+// ParseAndValidate parses and validates the request as Do does, resolving
+// its persisted query or trusted document, and returns the ExecuteParams of
+// its operation, to execute by Execute or ExecuteSubscription, e.g. once
+// the type of the operation is known. It returns the Result to send instead
+// if the request can not be executed.
+func ParseAndValidate(p Params) (ExecuteParams, *Result) {
+	AST, result := parseAndValidate(&p)
+	if result != nil {
+		return ExecuteParams{}, result
+	}
+	return executeParams(p, AST), nil
+}
+
+// parseAndValidate parses and validates the request, running the extension
+// hooks. It returns the Result to send instead of executing the request if
+// any of those fails.
+func parseAndValidate(p *Params) (*ast.Document, *Result) {
+	// resolve the request string of persisted queries
+	if err := resolvePersistedQuery(p); err != nil {
+		return nil, &Result{
+			Errors: presentErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), err),
+		}
+	}
+
+	// only execute trusted documents, if the schema has any
+	trusted, err := resolveTrustedDocument(p)
+	if err != nil {
+		return nil, &Result{
+			Errors: presentErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), err),
+		}
+	}
+
 	source := source.NewSource(&source.Source{
 		Body: []byte(p.RequestString),
 		Name: "GraphQL request",
 	})
 
 	// run init on the extensions
-	extErrs := handleExtensionsInits(&p)
+	extErrs := handleExtensionsInits(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	extErrs, parseFinishFn := handleExtensionsParseDidStart(&p)
+	// look the document up in the cache, trusted documents being already
+	// parsed and validated
+	cache := documentCache(p.Schema, p.DocumentCache)
+	var cached *cachedDocument
+	if trusted != nil {
+		cached = &cachedDocument{document: trusted.document}
+		p.Context = withDocumentCacheHit(p.Context)
+	} else if cache != nil {
+		cached = cache.get(p.Schema, p.RequestString)
+		if cached != nil {
+			p.Context = withDocumentCacheHit(p.Context)
+		}
+	}
+
+	extErrs, parseFinishFn := handleExtensionsParseDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
+	// parse the source, unless it is cached
+	var AST *ast.Document
+	if cached != nil {
+		AST = cached.document
+	} else {
+		AST, err = parser.Parse(parser.ParseParams{Source: source})
+	}
 	if err != nil {
 		// run parseFinishFuncs for extensions
 		extErrs = parseFinishFn(err)
 
 		// merge the errors from extensions and the original error from parser
-		extErrs = append(extErrs, gqlerrors.FormatErrors(err)...)
-		return &Result{
+		extErrs = append(extErrs, presentErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), err)...)
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
@@ -70,29 +165,44 @@ func Do(p Params) *Result {
 	// run parseFinish functions for extensions
 	extErrs = parseFinishFn(err)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
 	// notify extensions about the start of the validation
-	extErrs, validationFinishFn := handleExtensionsValidationDidStart(&p)
+	extErrs, validationFinishFn := handleExtensionsValidationDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+	// validate document, unless it is cached
+	var validationResult ValidationResult
+	if cached != nil {
+		validationResult = ValidationResult{
+			IsValid: len(cached.validationErrors) == 0,
+			Errors:  make([]gqlerrors.FormattedError, len(cached.validationErrors)),
+		}
+		copy(validationResult.Errors, cached.validationErrors)
+	} else {
+		validationResult = ValidateDocument(&p.Schema, AST, nil)
+		if cache != nil {
+			cache.add(p.Schema, p.RequestString, &cachedDocument{
+				document:         AST,
+				validationErrors: validationResult.Errors,
+			})
+		}
+	}
 
 	if !validationResult.IsValid {
 		// run validation finish functions for extensions
 		extErrs = validationFinishFn(validationResult.Errors)
 
 		// merge the errors from extensions and the original error from parser
-		extErrs = append(extErrs, validationResult.Errors...)
-		return &Result{
+		extErrs = append(extErrs, presentFormattedErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), validationResult.Errors)...)
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
@@ -100,17 +210,51 @@ func Do(p Params) *Result {
 	// run the validationFinishFuncs for extensions
 	extErrs = validationFinishFn(validationResult.Errors)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	return Execute(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
-	})
+	if err := checkOperationType(AST, p); err != nil {
+		return nil, &Result{
+			Errors: presentErrors(p.Context, errorPresenter(p.Schema, p.ErrorPresenter), err),
+		}
+	}
+
+	return AST, nil
+}
+
+// checkOperationType returns an error if the operation of the document is
+// not one of the AllowedOperationTypes of the request.
+func checkOperationType(AST *ast.Document, p *Params) error {
+	if len(p.AllowedOperationTypes) == 0 {
+		return nil
+	}
+	operation, _, err := selectOperation(AST, p.OperationName)
+	if err != nil {
+		return err
+	}
+	for _, operationType := range p.AllowedOperationTypes {
+		if operation.Operation == operationType {
+			return nil
+		}
+	}
+	return newCodedError(
+		fmt.Errorf("Can not perform a %v operation in this request.", operation.Operation),
+		gqlerrors.ErrCodeOperationNotAllowed,
+	)
+}
+
+// executeParams returns the ExecuteParams of the request.
+func executeParams(p Params, AST *ast.Document) ExecuteParams {
+	return ExecuteParams{
+		Schema:         p.Schema,
+		Root:           p.RootObject,
+		AST:            AST,
+		OperationName:  p.OperationName,
+		Args:           p.VariableValues,
+		Context:        p.Context,
+		ErrorPresenter: p.ErrorPresenter,
+		OrderedResults: p.OrderedResults,
+	}
 }
@@ -268,3 +268,31 @@ func TestEmptyStringIsNotNull(t *testing.T) {
 		t.Errorf("wrong result, query: %v, graphql result diff: %v", query, testutil.Diff(expected, result))
 	}
 }
+
+func TestParseAndValidate_ReturnsTheParamsOfTheOperation(t *testing.T) {
+	params, result := graphql.ParseAndValidate(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `query A { hero { name } } query B { hero { id } }`,
+		OperationName: "B",
+	})
+	if result != nil {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	if operation := params.Operation(); operation == nil || operation.Name.Value != "B" {
+		t.Fatalf("Unexpected operation: %v", operation)
+	}
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"hero": map[string]interface{}{"id": "2001"}},
+	}
+	if result := graphql.Execute(params); !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	_, result = graphql.ParseAndValidate(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ unknown }`,
+	})
+	if result == nil || !result.IsRequestError() {
+		t.Fatalf("Expected the validation errors, got %v", result)
+	}
+}
@@ -0,0 +1,400 @@
+// Package handler implements the GraphQL over HTTP spec, serving a schema
+// over HTTP:
+//
+//	https://graphql.github.io/graphql-over-http/draft/
+//
+// Queries are executed for GET requests, with the query, operationName,
+// variables and extensions query parameters, and every operation for POST
+// requests, whose body is either a JSON request or an array of requests, of
+// the application/json content type, or a query, of the application/graphql
+// content type. POST requests of the multipart/form-data content type hold
+// file uploads, see package upload, if the Upload option is set.
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/upload"
+)
+
+const (
+	// ContentTypeJSON is the media type of the JSON requests, and of the
+	// responses of the clients accepting it, or not sending any Accept
+	// header.
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeGraphQLResponse is the media type of the responses of the
+	// clients accepting it, whose status codes report the request errors.
+	ContentTypeGraphQLResponse = "application/graphql-response+json"
+
+	// ContentTypeGraphQL is the media type of the requests whose body is the
+	// query.
+	ContentTypeGraphQL = "application/graphql"
+
+	// DefaultMaxBodySize is the maximum size of the bodies of the requests,
+	// other than the multipart ones, unless set in the Config.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// preflightHeaders are the headers which can not be sent by a HTML form or
+// a simple cross-origin request, and so prove that a request was not forged.
+var preflightHeaders = []string{"GraphQL-Require-Preflight", "Apollo-Require-Preflight", "X-Apollo-Operation-Name"}
+
+// ContextFn returns the context of the execution of a request.
+type ContextFn func(r *http.Request) context.Context
+
+// RootObjectFn returns the root value of the execution of a request.
+type RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
+
+// ResponseFn is called with the response of a request before it is written,
+// and can change its status code, headers and results.
+type ResponseFn func(ctx context.Context, r *http.Request, response *Response)
+
+// Config are the options of a Handler.
+type Config struct {
+	// Schema is the schema served by the handler.
+	Schema *graphql.Schema
+
+	// Pretty makes the JSON responses be indented.
+	Pretty bool
+
+	// MaxBodySize is the maximum size in bytes of the bodies of the
+	// requests, other than the multipart ones whose limits are those of the
+	// Upload option, DefaultMaxBodySize if zero.
+	MaxBodySize int64
+
+	// UseNumber makes the numbers of the variables be decoded as
+	// json.Number values, see graphql.RequestDecoder.
+	UseNumber bool
+
+	// Upload, if set, enables the multipart requests of file uploads.
+	Upload *upload.Config
+
+	// DisableCSRFPrevention disables the rejection of the multipart requests
+	// without one of the GraphQL-Require-Preflight, Apollo-Require-Preflight
+	// or X-Apollo-Operation-Name headers. Such requests can be sent by HTML
+	// forms of any origin, unlike the requests of the other content types
+	// and headers, which require CORS preflight requests. GET requests,
+	// which can not execute mutations, are not affected.
+	DisableCSRFPrevention bool
+
+	// ContextFn returns the context of the execution of a request, the
+	// context of the request if nil.
+	ContextFn ContextFn
+
+	// RootObjectFn, if set, returns the root value of the execution of a
+	// request.
+	RootObjectFn RootObjectFn
+
+	// ResponseFn, if set, is called with the response of each request
+	// before it is written.
+	ResponseFn ResponseFn
+}
+
+// Response is the response to a request, before it is written.
+type Response struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Header is the header of the response, i.e. of the ResponseWriter.
+	Header http.Header
+
+	// ContentType is the negotiated media type of the response.
+	ContentType string
+
+	// Results are the results of the operations of the request.
+	Results []*graphql.Result
+
+	// Batch reports whether the request is a batch, whose results are
+	// written as a JSON array.
+	Batch bool
+}
+
+// Handler serves a schema over HTTP.
+type Handler struct {
+	config Config
+}
+
+// New returns a handler of the config, whose Schema must be set.
+func New(config *Config) *Handler {
+	if config == nil || config.Schema == nil {
+		panic("handler: undefined Schema")
+	}
+	return &Handler{config: *config}
+}
+
+// ServeHTTP executes the operations of the request and writes their results.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.config.ContextFn != nil {
+		ctx = h.config.ContextFn(r)
+	}
+	h.ContextHandler(ctx, w, r)
+}
+
+// ContextHandler executes the operations of the request in the context and
+// writes their results.
+func (h *Handler) ContextHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	response := &Response{
+		StatusCode: http.StatusOK,
+		Header:     w.Header(),
+	}
+	response.ContentType = negotiate(r.Header.Get("Accept"))
+	if response.ContentType == "" {
+		response.ContentType = ContentTypeJSON
+		h.fail(ctx, w, r, response, http.StatusNotAcceptable, "the Accept header must accept %v or %v", ContentTypeGraphQLResponse, ContentTypeJSON)
+		return
+	}
+
+	var (
+		requests []graphql.Request
+		status   int
+		err      error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		requests, status, err = h.getRequest(r)
+	case http.MethodPost:
+		var form *upload.Form
+		requests, response.Batch, form, status, err = h.postRequests(w, r)
+		if form != nil {
+			defer form.RemoveAll()
+		}
+	default:
+		response.Header.Set("Allow", "GET, POST")
+		status, err = http.StatusMethodNotAllowed, fmt.Errorf("the %v method is not supported", r.Method)
+	}
+	if err != nil {
+		h.fail(ctx, w, r, response, status, "%v", err)
+		return
+	}
+
+	var rootObject map[string]interface{}
+	if h.config.RootObjectFn != nil {
+		rootObject = h.config.RootObjectFn(ctx, r)
+	}
+	params := make([]graphql.Params, len(requests))
+	for i, request := range requests {
+		params[i] = request.Params(ctx, *h.config.Schema)
+		params[i].RootObject = rootObject
+		if r.Method == http.MethodGet {
+			// the operation is only known once Do resolves the persisted
+			// query or trusted document of the request
+			params[i].AllowedOperationTypes = []string{ast.OperationTypeQuery}
+		}
+	}
+	if response.Batch {
+		response.Results = graphql.DoBatch(params)
+	} else {
+		response.Results = []*graphql.Result{graphql.Do(params[0])}
+		if result := response.Results[0]; result.IsRequestError() {
+			if gqlerrors.Code(result.Errors[0]) == gqlerrors.ErrCodeOperationNotAllowed {
+				response.StatusCode = http.StatusMethodNotAllowed
+			} else if response.ContentType == ContentTypeGraphQLResponse {
+				response.StatusCode = http.StatusBadRequest
+			}
+		}
+	}
+	h.write(ctx, w, r, response)
+}
+
+// getRequest reads the request of the query parameters of a GET request.
+func (h *Handler) getRequest(r *http.Request) ([]graphql.Request, int, error) {
+	values := r.URL.Query()
+	request := graphql.Request{
+		Query:         values.Get("query"),
+		OperationName: values.Get("operationName"),
+		DocumentID:    values.Get("documentId"),
+	}
+	for name, value := range map[string]*map[string]interface{}{
+		"variables":  &request.Variables,
+		"extensions": &request.Extensions,
+	} {
+		if values.Get(name) == "" {
+			continue
+		}
+		if err := h.decodeJSON(values.Get(name), value); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid %v parameter: %v", name, err)
+		}
+	}
+	if request.Query == "" && request.DocumentID == "" && request.Extensions == nil {
+		return nil, http.StatusBadRequest, errors.New("missing query parameter")
+	}
+	return []graphql.Request{request}, http.StatusOK, nil
+}
+
+// postRequests reads the requests of the body of a POST request, and its
+// uploaded files, to be removed once the requests are executed.
+func (h *Handler) postRequests(w http.ResponseWriter, r *http.Request) ([]graphql.Request, bool, *upload.Form, int, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, false, nil, http.StatusUnsupportedMediaType, errors.New("missing or invalid Content-Type header")
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return nil, false, nil, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported charset %v", charset)
+	}
+
+	if mediaType == "multipart/form-data" && h.config.Upload != nil {
+		if !h.config.DisableCSRFPrevention && !hasPreflightHeader(r) {
+			return nil, false, nil, http.StatusBadRequest, fmt.Errorf("multipart requests must have a %v header, to prevent cross-site request forgery", preflightHeaders[0])
+		}
+		form, err := upload.Parse(r, *h.config.Upload)
+		if err == upload.ErrFileTooLarge {
+			return nil, false, nil, http.StatusRequestEntityTooLarge, err
+		}
+		if err != nil {
+			return nil, false, nil, http.StatusBadRequest, err
+		}
+		return form.Requests, form.Batch, form, http.StatusOK, nil
+	}
+
+	maxBodySize := h.config.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		return nil, false, nil, http.StatusRequestEntityTooLarge, fmt.Errorf("the body is larger than %v bytes", maxBodySize)
+	}
+
+	switch mediaType {
+	case ContentTypeJSON:
+		decoder := graphql.NewRequestDecoder(bytes.NewReader(body))
+		if h.config.UseNumber {
+			decoder.UseNumber()
+		}
+		requests, batch, err := decoder.Decode()
+		if err != nil {
+			return nil, false, nil, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err)
+		}
+		for _, request := range requests {
+			if request.Query == "" && request.DocumentID == "" && request.Extensions == nil {
+				return nil, false, nil, http.StatusBadRequest, errors.New("missing query")
+			}
+		}
+		return requests, batch, nil, http.StatusOK, nil
+	case ContentTypeGraphQL:
+		return []graphql.Request{{
+			Query:         string(body),
+			OperationName: r.URL.Query().Get("operationName"),
+		}}, false, nil, http.StatusOK, nil
+	}
+	return nil, false, nil, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported Content-Type %v", mediaType)
+}
+
+func (h *Handler) decodeJSON(value string, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	if h.config.UseNumber {
+		decoder.UseNumber()
+	}
+	return decoder.Decode(v)
+}
+
+// fail writes the error of a request which can not be executed.
+func (h *Handler) fail(ctx context.Context, w http.ResponseWriter, r *http.Request, response *Response, status int, format string, a ...interface{}) {
+	err := gqlerrors.NewFormattedError(fmt.Sprintf(format, a...))
+	err.Extensions = map[string]interface{}{"code": gqlerrors.ErrCodeBadRequest}
+	response.StatusCode = status
+	response.Results = []*graphql.Result{{Errors: []gqlerrors.FormattedError{err}}}
+	h.write(ctx, w, r, response)
+}
+
+func (h *Handler) write(ctx context.Context, w http.ResponseWriter, r *http.Request, response *Response) {
+	if h.config.ResponseFn != nil {
+		h.config.ResponseFn(ctx, r, response)
+	}
+	bodies := make([]interface{}, len(response.Results))
+	for i, result := range response.Results {
+		bodies[i] = result
+		if result.IsRequestError() {
+			// the data entry is absent from the results of the requests
+			// which were not executed, rather than null
+			bodies[i] = struct {
+				Errors     []gqlerrors.FormattedError `json:"errors"`
+				Extensions map[string]interface{}     `json:"extensions,omitempty"`
+			}{result.Errors, result.Extensions}
+		}
+	}
+	var body interface{} = bodies
+	if !response.Batch && len(bodies) == 1 {
+		body = bodies[0]
+	}
+	var (
+		buff []byte
+		err  error
+	)
+	if h.config.Pretty {
+		buff, err = json.MarshalIndent(body, "", "\t")
+	} else {
+		buff, err = json.Marshal(body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response.Header.Set("Content-Type", response.ContentType+"; charset=utf-8")
+	response.Header.Set("Content-Length", strconv.Itoa(len(buff)))
+	w.WriteHeader(response.StatusCode)
+	w.Write(buff)
+}
+
+func hasPreflightHeader(r *http.Request) bool {
+	for _, header := range preflightHeaders {
+		if r.Header.Get(header) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiate returns the media type of the response accepted by the Accept
+// header, preferring ContentTypeGraphQLResponse, or "" if none is.
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeJSON
+	}
+	var best string
+	var bestQuality float64
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		var candidate string
+		switch mediaType {
+		case ContentTypeGraphQLResponse, ContentTypeJSON:
+			candidate = mediaType
+		case "application/*", "*/*":
+			// clients accepting any media type, e.g. browsers, may not know
+			// the newer media type
+			candidate = ContentTypeJSON
+		default:
+			continue
+		}
+		if quality > bestQuality || (quality == bestQuality && candidate == ContentTypeGraphQLResponse) {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best
+}
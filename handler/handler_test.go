@@ -0,0 +1,301 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/handler"
+	"github.com/graphql-go/graphql/upload"
+)
+
+type userKey struct{}
+
+func testSchema(t *testing.T) *graphql.Schema {
+	return testSchemaWithStore(t, nil)
+}
+
+func testSchemaWithStore(t *testing.T, store graphql.PersistedQueryStore) *graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		PersistedQueryStore: store,
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"name": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if name, ok := p.Args["name"].(string); ok {
+							return "Hello " + name, nil
+						}
+						return "Hello", nil
+					},
+				},
+				"user": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Context.Value(userKey{}), nil
+					},
+				},
+				"root": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Info.RootValue.(map[string]interface{})["root"], nil
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"size": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"file": &graphql.ArgumentConfig{Type: upload.Upload},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if file, ok := p.Args["file"].(*upload.File); ok {
+							return file.Size, nil
+						}
+						return 0, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return &schema
+}
+
+func serve(h http.Handler, r *http.Request) (*httptest.ResponseRecorder, interface{}) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	var body interface{}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestHandler_ExecutesQueriesOfGETRequests(t *testing.T) {
+	h := handler.New(&handler.Config{Schema: testSchema(t)})
+	values := url.Values{
+		"query":     {"query($name: String) { hello(name: $name) }"},
+		"variables": {`{"name": "Alice"}`},
+	}
+	w, body := serve(h, httptest.NewRequest("GET", "/graphql?"+values.Encode(), nil))
+	expected := map[string]interface{}{"data": map[string]interface{}{"hello": "Hello Alice"}}
+	if w.Code != http.StatusOK || !reflect.DeepEqual(expected, body) {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Fatalf("Unexpected Content-Type: %v", contentType)
+	}
+
+	values = url.Values{"query": {"mutation { size }"}}
+	w, _ = serve(h, httptest.NewRequest("GET", "/graphql?"+values.Encode(), nil))
+	if w.Code != http.StatusMethodNotAllowed || !strings.Contains(w.Body.String(), "OPERATION_NOT_ALLOWED") {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+
+	values = url.Values{"query": {"{ hello }"}, "variables": {`{`}}
+	w, _ = serve(h, httptest.NewRequest("GET", "/graphql?"+values.Encode(), nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"code":"BAD_REQUEST"`) {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+}
+
+func TestHandler_RejectsPersistedMutationsOfGETRequests(t *testing.T) {
+	store := graphql.NewLRUPersistedQueryStore(10)
+	h := handler.New(&handler.Config{Schema: testSchemaWithStore(t, store)})
+	mutation := "mutation { size }"
+	sum := sha256.Sum256([]byte(mutation))
+	hash := hex.EncodeToString(sum[:])
+	extensions := `{"persistedQuery": {"version": 1, "sha256Hash": "` + hash + `"}}`
+
+	// the operation of unknown hashes is only known once registered
+	values := url.Values{"extensions": {extensions}}
+	w, _ := serve(h, httptest.NewRequest("GET", "/graphql?"+values.Encode(), nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "PERSISTED_QUERY_NOT_FOUND") {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+
+	store.Set(context.Background(), hash, mutation)
+	w, _ = serve(h, httptest.NewRequest("GET", "/graphql?"+values.Encode(), nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+}
+
+func TestHandler_ExecutesPOSTRequests(t *testing.T) {
+	h := handler.New(&handler.Config{Schema: testSchema(t)})
+	tests := []struct {
+		contentType, body string
+		expected          interface{}
+	}{
+		{
+			contentType: "application/json",
+			body:        `{"query": "{ hello }"}`,
+			expected:    map[string]interface{}{"data": map[string]interface{}{"hello": "Hello"}},
+		},
+		{
+			contentType: "application/json; charset=utf-8",
+			body:        `[{"query": "{ hello }"}, {"query": "mutation { size }"}]`,
+			expected: []interface{}{
+				map[string]interface{}{"data": map[string]interface{}{"hello": "Hello"}},
+				map[string]interface{}{"data": map[string]interface{}{"size": 0.0}},
+			},
+		},
+		{
+			contentType: "application/graphql",
+			body:        `{ hello(name: "Bob") }`,
+			expected:    map[string]interface{}{"data": map[string]interface{}{"hello": "Hello Bob"}},
+		},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/graphql", strings.NewReader(test.body))
+		r.Header.Set("Content-Type", test.contentType)
+		w, body := serve(h, r)
+		if w.Code != http.StatusOK || !reflect.DeepEqual(test.expected, body) {
+			t.Fatalf("Unexpected response for %v: %v %v", test.body, w.Code, w.Body)
+		}
+	}
+
+	for _, test := range []struct {
+		contentType, body string
+		status            int
+	}{
+		{"text/plain", `{"query": "{ hello }"}`, http.StatusUnsupportedMediaType},
+		{"application/x-www-form-urlencoded", `query=%7B+hello+%7D`, http.StatusUnsupportedMediaType},
+		{"application/json; charset=latin1", `{"query": "{ hello }"}`, http.StatusUnsupportedMediaType},
+		{"application/json", `{"query": `, http.StatusBadRequest},
+		{"application/json", `{}`, http.StatusBadRequest},
+		{"application/json", `{"query": "` + strings.Repeat(" ", handler.DefaultMaxBodySize) + `{ hello }"}`, http.StatusRequestEntityTooLarge},
+	} {
+		r := httptest.NewRequest("POST", "/graphql", strings.NewReader(test.body))
+		r.Header.Set("Content-Type", test.contentType)
+		w, body := serve(h, r)
+		if w.Code != test.status {
+			t.Fatalf("Unexpected response for %v: %v %v", test.contentType, w.Code, w.Body)
+		}
+		if errs, _ := body.(map[string]interface{})["errors"].([]interface{}); len(errs) != 1 {
+			t.Fatalf("Unexpected body for %v: %v", test.contentType, w.Body)
+		}
+	}
+
+	w, _ := serve(h, httptest.NewRequest("PUT", "/graphql", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, POST" {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Header())
+	}
+}
+
+func TestHandler_NegotiatesTheResponseMediaType(t *testing.T) {
+	h := handler.New(&handler.Config{Schema: testSchema(t)})
+	tests := []struct {
+		accept, query string
+		status        int
+		contentType   string
+		hasData       bool
+	}{
+		{"application/graphql-response+json, application/json;q=0.9", "{ hello }", http.StatusOK, "application/graphql-response+json", true},
+		{"application/graphql-response+json", "{ unknown }", http.StatusBadRequest, "application/graphql-response+json", false},
+		{"application/json", "{ unknown }", http.StatusOK, "application/json", false},
+		{"*/*", "{ hello }", http.StatusOK, "application/json", true},
+		{"application/graphql-response+json;q=0.5, application/json", "{ hello }", http.StatusOK, "application/json", true},
+		{"text/html", "{ hello }", http.StatusNotAcceptable, "application/json", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "`+test.query+`"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", test.accept)
+		w, body := serve(h, r)
+		if w.Code != test.status || w.Header().Get("Content-Type") != test.contentType+"; charset=utf-8" {
+			t.Fatalf("Unexpected response for %v: %v %v", test.accept, w.Code, w.Header())
+		}
+		if _, hasData := body.(map[string]interface{})["data"]; hasData != test.hasData {
+			t.Fatalf("Unexpected body for %v: %v", test.accept, w.Body)
+		}
+	}
+}
+
+func TestHandler_UsesTheContextRootObjectAndResponseFunctions(t *testing.T) {
+	h := handler.New(&handler.Config{
+		Schema: testSchema(t),
+		Pretty: true,
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), userKey{}, r.Header.Get("X-User"))
+		},
+		RootObjectFn: func(ctx context.Context, r *http.Request) map[string]interface{} {
+			return map[string]interface{}{"root": r.URL.Path}
+		},
+		ResponseFn: func(ctx context.Context, r *http.Request, response *handler.Response) {
+			response.Header.Set("X-User", ctx.Value(userKey{}).(string))
+			response.Results[0].Extensions = map[string]interface{}{"cost": 1}
+		},
+	})
+	r := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "{ user root }"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-User", "alice")
+	w, body := serve(h, r)
+	expected := map[string]interface{}{
+		"data":       map[string]interface{}{"user": "alice", "root": "/graphql"},
+		"extensions": map[string]interface{}{"cost": 1.0},
+	}
+	if w.Code != http.StatusOK || !reflect.DeepEqual(expected, body) || w.Header().Get("X-User") != "alice" {
+		t.Fatalf("Unexpected response: %v %v %v", w.Code, w.Header(), w.Body)
+	}
+	if !strings.Contains(w.Body.String(), "\n\t") {
+		t.Fatalf("Expected a pretty response, got %v", w.Body)
+	}
+}
+
+func TestHandler_ParsesUploadsWithAPreflightHeader(t *testing.T) {
+	h := handler.New(&handler.Config{
+		Schema: testSchema(t),
+		Upload: &upload.Config{MaxFileSize: 16},
+	})
+	newRequest := func(content string) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("operations", `{"query": "mutation($file: Upload) { size(file: $file) }", "variables": {"file": null}}`)
+		writer.WriteField("map", `{"0": ["variables.file"]}`)
+		part, _ := writer.CreateFormFile("0", "a.txt")
+		part.Write([]byte(content))
+		writer.Close()
+		r := httptest.NewRequest("POST", "/graphql", &body)
+		r.Header.Set("Content-Type", writer.FormDataContentType())
+		return r
+	}
+
+	w, _ := serve(h, newRequest("alpha"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected forgeable requests to be rejected, got %v %v", w.Code, w.Body)
+	}
+
+	r := newRequest("alpha")
+	r.Header.Set("GraphQL-Require-Preflight", "1")
+	w, body := serve(h, r)
+	expected := map[string]interface{}{"data": map[string]interface{}{"size": 5.0}}
+	if w.Code != http.StatusOK || !reflect.DeepEqual(expected, body) {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+
+	r = newRequest(strings.Repeat("a", 17))
+	r.Header.Set("GraphQL-Require-Preflight", "1")
+	w, _ = serve(h, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Unexpected response: %v %v", w.Code, w.Body)
+	}
+}
@@ -626,6 +626,15 @@ func init() {
 	TypeType.AddFieldConfig("ofType", &Field{
 		Type: TypeType,
 	})
+	TypeType.AddFieldConfig("specifiedByURL", &Field{
+		Type: String,
+		Resolve: func(p ResolveParams) (interface{}, error) {
+			if ttype, ok := p.Source.(*Scalar); ok && ttype.SpecifiedByURL() != "" {
+				return ttype.SpecifiedByURL(), nil
+			}
+			return nil, nil
+		},
+	})
 
 	SchemaType.ensureCache()
 	DirectiveType.ensureCache()
@@ -1306,6 +1306,7 @@ func TestIntrospection_FailsAsExpectedOnThe__TypeRootFieldWithoutAnArg(t *testin
 				Locations: []location.SourceLocation{
 					{Line: 3, Column: 9},
 				},
+				Extensions: testutil.RuleErrorExtensions(graphql.ProvidedNonNullArgumentsRule),
 			},
 		},
 	}
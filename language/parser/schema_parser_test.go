@@ -821,6 +821,7 @@ input Hello {
 		Locations: []location.SourceLocation{
 			{Line: 3, Column: 8},
 		},
+		Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeParseFailed},
 	}
 	if err == nil {
 		t.Fatalf("expected error, expected: %v, got: %v", expectedError, nil)
@@ -266,6 +266,7 @@ func TestLists_NonNullListOfNullableObjectsReturnsNull(t *testing.T) {
 					"nest",
 					"test",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -340,6 +341,7 @@ func TestLists_NonNullListOfNullableFunc_ReturnsNull(t *testing.T) {
 					"nest",
 					"test",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -441,6 +443,7 @@ func TestLists_NullableListOfNonNullObjects_ContainsNull(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -511,6 +514,7 @@ func TestLists_NullableListOfNonNullFunc_ContainsNull(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -602,6 +606,7 @@ func TestLists_NullableListOfNonNullArrayOfFunc_ContainsNulls(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -648,6 +653,7 @@ func TestLists_NonNullListOfNonNullObjects_ContainsNull(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -672,6 +678,7 @@ func TestLists_NonNullListOfNonNullObjects_ReturnsNull(t *testing.T) {
 					"nest",
 					"test",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -728,6 +735,7 @@ func TestLists_NonNullListOfNonNullFunc_ContainsNull(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -758,6 +766,7 @@ func TestLists_NonNullListOfNonNullFunc_ReturnsNull(t *testing.T) {
 					"nest",
 					"test",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -830,6 +839,7 @@ func TestLists_NonNullListOfNonNullArrayOfFunc_ContainsNulls(t *testing.T) {
 					"test",
 					1,
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -858,6 +868,7 @@ func TestLists_UserErrorExpectIterableButDidNotGetOne(t *testing.T) {
 					"nest",
 					"test",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
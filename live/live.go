@@ -0,0 +1,337 @@
+// Package live implements live queries, the queries of the @live directive,
+// whose results are sent again each time they change:
+//
+//	query @live {
+//		todos { id text done }
+//	}
+//
+// While executing a live query, the resolvers track the invalidation keys
+// of the resources they read, and the query is re-executed each time one of
+// them is invalidated, e.g. once the resource is updated:
+//
+//	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+//		live.Track(p.Context, "Todo:"+id)
+//		return store.Todo(id)
+//	}
+//
+//	store.UpdateTodo(todo)
+//	l.Invalidate("Todo:" + todo.ID)
+//
+// The Directive must be added to the directives of the schema to validate
+// the live queries.
+package live
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/pubsub"
+)
+
+// DefaultThrottle is the default minimum interval between the executions of
+// a live query.
+const DefaultThrottle = 500 * time.Millisecond
+
+// topicPrefix prefixes the invalidation keys in the topics of the broker.
+const topicPrefix = "live:"
+
+// Directive is the @live directive of the live queries.
+var Directive = graphql.NewDirective(graphql.DirectiveConfig{
+	Name: "live",
+	Description: "Directs the server to send the result of the query again " +
+		"each time it changes.",
+	Locations: []string{
+		graphql.DirectiveLocationQuery,
+	},
+})
+
+// Config is the configuration of a Live.
+type Config struct {
+	// PubSub is the broker of the invalidations, shared by the servers of
+	// the live queries if they are several, a pubsub.Memory if nil.
+	PubSub pubsub.PubSub
+
+	// Throttle is the minimum interval between the executions of a live
+	// query, the invalidations made in the meantime being batched,
+	// DefaultThrottle if 0.
+	Throttle time.Duration
+
+	// Patches, if true, sends the results following the first one of a live
+	// query as the JSON Patch, RFC 6902, of its data, in the "patch"
+	// extension of results without data. The results with errors are sent
+	// in full.
+	Patches bool
+}
+
+// Live executes the live queries.
+type Live struct {
+	config Config
+}
+
+// New returns a Live of the configuration, which can be nil for the default
+// one.
+func New(config *Config) *Live {
+	l := &Live{}
+	if config != nil {
+		l.config = *config
+	}
+	if l.config.PubSub == nil {
+		l.config.PubSub = pubsub.NewMemory(&pubsub.MemoryConfig{BufferSize: 1})
+	}
+	if l.config.Throttle == 0 {
+		l.config.Throttle = DefaultThrottle
+	}
+	return l
+}
+
+// Invalidate re-executes the live queries which tracked one of the keys.
+func (l *Live) Invalidate(keys ...string) error {
+	for _, key := range keys {
+		if err := l.config.PubSub.Publish(topicPrefix+key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Do executes the operation like graphql.Do and returns the channel of its
+// results like graphql.Subscribe. The result of a live query is sent again
+// each time it changes, until the context of the params is done, whereas
+// the channel of the other operations, and of the invalid live queries, is
+// closed after their result.
+func (l *Live) Do(p graphql.Params) chan *graphql.Result {
+	// the request is parsed and validated once, its executions only
+	// resolving the fields
+	params, result := graphql.ParseAndValidate(p)
+	if result == nil && !isLiveQuery(params) {
+		result = graphql.Execute(params)
+	}
+	if result != nil {
+		results := make(chan *graphql.Result, 1)
+		results <- result
+		close(results)
+		return results
+	}
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	results := make(chan *graphql.Result)
+	go func() {
+		defer close(results)
+		w := &watcher{
+			ctx:         ctx,
+			pubsub:      l.config.PubSub,
+			invalidated: make(chan struct{}, 1),
+			watches:     map[string]*keyWatch{},
+		}
+		defer w.close()
+
+		var previous *graphql.Result
+		for {
+			// the invalidations made from now on are seen by the execution,
+			// or re-execute the query
+			select {
+			case <-w.invalidated:
+			default:
+			}
+			started := time.Now()
+			t := &tracker{watcher: w, keys: map[string]struct{}{}}
+			execution := params
+			execution.Context = context.WithValue(ctx, trackerKey{}, t)
+			result := graphql.Execute(execution)
+			w.retain(t.trackedKeys())
+			if ctx.Err() != nil {
+				return
+			}
+
+			if next := l.next(previous, result); next != nil {
+				select {
+				case results <- next:
+				case <-ctx.Done():
+					return
+				}
+			}
+			// the live queries which are not executed, e.g. invalid, end
+			if result.IsRequestError() {
+				return
+			}
+			previous = result
+
+			select {
+			case <-w.invalidated:
+			case <-ctx.Done():
+				return
+			}
+			if wait := l.config.Throttle - time.Since(started); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+		}
+	}()
+	return results
+}
+
+// next returns the result to send after the previous one, nil if unchanged.
+func (l *Live) next(previous, result *graphql.Result) *graphql.Result {
+	if previous == nil {
+		return result
+	}
+	if reflect.DeepEqual(previous.Data, result.Data) && reflect.DeepEqual(previous.Errors, result.Errors) {
+		return nil
+	}
+	if !l.config.Patches || previous.HasErrors() || result.HasErrors() {
+		return result
+	}
+	extensions := map[string]interface{}{}
+	for name, value := range result.Extensions {
+		extensions[name] = value
+	}
+	extensions["patch"] = Diff(previous.Data, result.Data)
+	return &graphql.Result{Extensions: extensions}
+}
+
+// isLiveQuery reports whether the operation of the params is a query of the
+// @live directive.
+func isLiveQuery(p graphql.ExecuteParams) bool {
+	operation := p.Operation()
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return false
+	}
+	for _, directive := range operation.Directives {
+		if directive.Name != nil && directive.Name.Value == Directive.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// trackerKey is the key of the tracker of an execution in its context.
+type trackerKey struct{}
+
+// Track tracks the invalidation keys of the resources read by a resolver of
+// a live query, which is re-executed once one of them is invalidated. It
+// does nothing for the other operations.
+func Track(ctx context.Context, keys ...string) {
+	t, ok := ctx.Value(trackerKey{}).(*tracker)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := t.keys[key]; ok {
+			continue
+		}
+		t.keys[key] = struct{}{}
+		t.watcher.watch(key)
+	}
+}
+
+// tracker is the set of the keys tracked by an execution of a live query.
+type tracker struct {
+	watcher *watcher
+
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+func (t *tracker) trackedKeys() map[string]struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.keys
+}
+
+// watcher watches the invalidations of the keys of a live query.
+type watcher struct {
+	ctx         context.Context
+	pubsub      pubsub.PubSub
+	invalidated chan struct{}
+
+	mu      sync.Mutex
+	watches map[string]*keyWatch
+}
+
+// keyWatch is the subscription to the invalidations of a key.
+type keyWatch struct {
+	stream graphql.SourceStream
+}
+
+// watch subscribes to the invalidations of the key, as soon as it is
+// tracked, so that the ones made during the execution are not missed.
+func (w *watcher) watch(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.watches[key]; ok {
+		return
+	}
+	stream, err := w.pubsub.Subscribe(w.ctx, topicPrefix+key)
+	if err != nil {
+		// the key can not be watched, so the query is re-executed as if it
+		// was invalidated, i.e. polled at the throttle interval
+		w.invalidate()
+		return
+	}
+	kw := &keyWatch{stream: stream}
+	w.watches[key] = kw
+	go func() {
+		for {
+			_, err := stream.Next(w.ctx)
+			if err == nil {
+				w.invalidate()
+				continue
+			}
+			if w.ctx.Err() != nil {
+				return
+			}
+			// the watch ended, e.g. as the connection to the broker was lost,
+			// unless it was stopped: the query is re-executed, which watches
+			// the key again, so that it is polled at the throttle interval
+			// while the broker fails
+			w.mu.Lock()
+			ended := w.watches[key] == kw
+			if ended {
+				delete(w.watches, key)
+			}
+			w.mu.Unlock()
+			if ended {
+				stream.Close()
+				w.invalidate()
+			}
+			return
+		}
+	}()
+}
+
+func (w *watcher) invalidate() {
+	select {
+	case w.invalidated <- struct{}{}:
+	default:
+	}
+}
+
+// retain stops watching the keys which are not in keys.
+func (w *watcher) retain(keys map[string]struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, kw := range w.watches {
+		if _, ok := keys[key]; !ok {
+			kw.stream.Close()
+			delete(w.watches, key)
+		}
+	}
+}
+
+func (w *watcher) close() {
+	w.retain(nil)
+}
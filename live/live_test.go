@@ -0,0 +1,271 @@
+package live_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/live"
+)
+
+// store is a store of the texts of todos.
+type store struct {
+	mu    sync.Mutex
+	texts map[string]string
+}
+
+func (s *store) set(id, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts[id] = text
+}
+
+func testSchema(t *testing.T, s *store) graphql.Schema {
+	todoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Todo",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.ID},
+			"text": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"todo": &graphql.Field{
+					Type: todoType,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.ID},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id := p.Args["id"].(string)
+						live.Track(p.Context, "Todo:"+id)
+						s.mu.Lock()
+						defer s.mu.Unlock()
+						return map[string]interface{}{"id": id, "text": s.texts[id]}, nil
+					},
+				},
+			},
+		}),
+		Directives: append(append([]*graphql.Directive{}, graphql.SpecifiedDirectives...), live.Directive),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func receive(t *testing.T, results chan *graphql.Result) *graphql.Result {
+	select {
+	case result := <-results:
+		if result == nil || result.HasErrors() {
+			t.Fatalf("Unexpected result: %v", result)
+		}
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a result")
+	}
+	return nil
+}
+
+func TestLive_ReexecutesTheQueriesOfInvalidatedKeys(t *testing.T) {
+	s := &store{texts: map[string]string{"1": "a", "2": "b"}}
+	l := live.New(&live.Config{Throttle: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := l.Do(graphql.Params{
+		Schema:        testSchema(t, s),
+		RequestString: `query @live { todo(id: "1") { text } }`,
+		Context:       ctx,
+	})
+
+	expected := map[string]interface{}{"todo": map[string]interface{}{"text": "a"}}
+	if result := receive(t, results); !reflect.DeepEqual(expected, result.Data) {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+
+	// the unchanged results are not sent
+	l.Invalidate("Todo:1")
+	// the keys which are not tracked do not re-execute the query
+	s.set("2", "c")
+	l.Invalidate("Todo:2")
+	s.set("1", "d")
+	l.Invalidate("Todo:1")
+	expected = map[string]interface{}{"todo": map[string]interface{}{"text": "d"}}
+	if result := receive(t, results); !reflect.DeepEqual(expected, result.Data) {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Fatalf("Expected the results to end once the context is done")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the results to end once the context is done")
+	}
+}
+
+func TestLive_SendsPatches(t *testing.T) {
+	s := &store{texts: map[string]string{"1": "a"}}
+	l := live.New(&live.Config{Throttle: time.Millisecond, Patches: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := l.Do(graphql.Params{
+		Schema:        testSchema(t, s),
+		RequestString: `query @live { todo(id: "1") { id text } }`,
+		Context:       ctx,
+	})
+	receive(t, results)
+
+	s.set("1", "b")
+	l.Invalidate("Todo:1")
+	result := receive(t, results)
+	expected := []live.Operation{{Op: "replace", Path: "/todo/text", Value: "b"}}
+	if result.Data != nil || !reflect.DeepEqual(expected, result.Extensions["patch"]) {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
+
+func TestLive_SendsPatchesOfOrderedResults(t *testing.T) {
+	s := &store{texts: map[string]string{"1": "a"}}
+	l := live.New(&live.Config{Throttle: time.Millisecond, Patches: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := l.Do(graphql.Params{
+		Schema:         testSchema(t, s),
+		RequestString:  `query @live { todo(id: "1") { id text } }`,
+		Context:        ctx,
+		OrderedResults: true,
+	})
+	receive(t, results)
+
+	s.set("1", "b")
+	l.Invalidate("Todo:1")
+	result := receive(t, results)
+	expected := []live.Operation{{Op: "replace", Path: "/todo/text", Value: "b"}}
+	if result.Data != nil || !reflect.DeepEqual(expected, result.Extensions["patch"]) {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
+
+// failingPubSub is a broker whose subscriptions fail.
+type failingPubSub struct {
+	mu    sync.Mutex
+	nexts int
+}
+
+func (ps *failingPubSub) Publish(topic string, payload interface{}) error {
+	return nil
+}
+
+func (ps *failingPubSub) Subscribe(ctx context.Context, topics ...string) (graphql.SourceStream, error) {
+	return ps, nil
+}
+
+func (ps *failingPubSub) Next(ctx context.Context) (interface{}, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.nexts++
+	return nil, errors.New("connection lost")
+}
+
+func (ps *failingPubSub) Close() error {
+	return nil
+}
+
+func TestLive_PollsWhileTheBrokerFails(t *testing.T) {
+	s := &store{texts: map[string]string{"1": "a"}}
+	ps := &failingPubSub{}
+	l := live.New(&live.Config{PubSub: ps, Throttle: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := l.Do(graphql.Params{
+		Schema:        testSchema(t, s),
+		RequestString: `query @live { todo(id: "1") { text } }`,
+		Context:       ctx,
+	})
+	receive(t, results)
+
+	s.set("1", "b")
+	expected := map[string]interface{}{"todo": map[string]interface{}{"text": "b"}}
+	if result := receive(t, results); !reflect.DeepEqual(expected, result.Data) {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+	time.Sleep(50 * time.Millisecond)
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	// the watches end on their first error, rather than spinning
+	if ps.nexts > 20 {
+		t.Fatalf("Expected the failing watches to be polled, got %v calls to Next", ps.nexts)
+	}
+}
+
+func TestLive_ExecutesTheOtherOperationsOnce(t *testing.T) {
+	s := &store{texts: map[string]string{"1": "a"}}
+	l := live.New(nil)
+	for _, query := range []string{`{ todo(id: "1") { text } }`, `query @live { unknown }`} {
+		results := l.Do(graphql.Params{
+			Schema:        testSchema(t, s),
+			RequestString: query,
+		})
+		if result, ok := <-results; !ok || result == nil {
+			t.Fatalf("Expected a result of %v", query)
+		}
+		if _, ok := <-results; ok {
+			t.Fatalf("Expected a single result of %v", query)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := map[string]interface{}{
+		"a":   1,
+		"b":   []interface{}{1, 2},
+		"c":   []interface{}{1},
+		"d/~": true,
+	}
+	to := map[string]interface{}{
+		"a": 2,
+		"b": []interface{}{1, 3},
+		"c": []interface{}{1, 2},
+		"e": nil,
+	}
+	expected := `[` +
+		`{"op":"remove","path":"/d~1~0"},` +
+		`{"op":"replace","path":"/a","value":2},` +
+		`{"op":"replace","path":"/b/1","value":3},` +
+		`{"op":"replace","path":"/c","value":[1,2]},` +
+		`{"op":"add","path":"/e","value":null}` +
+		`]`
+	patch, err := json.Marshal(live.Diff(from, to))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(patch) != expected {
+		t.Fatalf("Expected patch %s, got %s", expected, patch)
+	}
+	if ops := live.Diff(from, from); len(ops) != 0 {
+		t.Fatalf("Expected no operation, got %v", ops)
+	}
+
+	orderedFrom := graphql.NewOrderedMap()
+	orderedFrom.Set("b", 1)
+	orderedFrom.Set("a", 1)
+	orderedTo := graphql.NewOrderedMap()
+	orderedTo.Set("b", 2)
+	orderedTo.Set("a", 2)
+	expectedOps := []live.Operation{
+		{Op: "replace", Path: "/b", Value: 2},
+		{Op: "replace", Path: "/a", Value: 2},
+	}
+	if ops := live.Diff(orderedFrom, orderedTo); !reflect.DeepEqual(expectedOps, ops) {
+		t.Fatalf("Expected operations in the order of the fields %v, got %v", expectedOps, ops)
+	}
+}
@@ -0,0 +1,98 @@
+package live
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Operation is an operation of a JSON Patch, see RFC 6902.
+type Operation struct {
+	// Op is the operation, "add", "remove" or "replace".
+	Op string
+	// Path is the JSON Pointer, see RFC 6901, of the value of the operation.
+	Path string
+	// Value is the value added or replacing the previous one.
+	Value interface{}
+}
+
+// MarshalJSON encodes the operation, without value if it is a remove.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(map[string]interface{}{"op": op.Op, "path": op.Path})
+	}
+	return json.Marshal(map[string]interface{}{"op": op.Op, "path": op.Path, "value": op.Value})
+}
+
+// Diff returns the JSON Patch of the operations changing the data from into
+// the data to, the data being the ones of results. The objects, maps or
+// *graphql.OrderedMap values, are patched by field, and the lists by item if
+// their length is unchanged, the other values being replaced.
+func Diff(from, to interface{}) []Operation {
+	var ops []Operation
+	diff(&ops, "", from, to)
+	return ops
+}
+
+func diff(ops *[]Operation, path string, from, to interface{}) {
+	if toNames, toValue, ok := object(to); ok {
+		if fromNames, fromValue, ok := object(from); ok {
+			for _, name := range fromNames {
+				if _, ok := toValue(name); !ok {
+					*ops = append(*ops, Operation{Op: "remove", Path: path + "/" + escape(name)})
+				}
+			}
+			for _, name := range toNames {
+				value, _ := toValue(name)
+				if previous, ok := fromValue(name); ok {
+					diff(ops, path+"/"+escape(name), previous, value)
+				} else {
+					*ops = append(*ops, Operation{Op: "add", Path: path + "/" + escape(name), Value: value})
+				}
+			}
+			return
+		}
+	}
+	if to, ok := to.([]interface{}); ok {
+		if from, ok := from.([]interface{}); ok && len(from) == len(to) {
+			for i := range to {
+				diff(ops, path+"/"+strconv.Itoa(i), from[i], to[i])
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(from, to) {
+		*ops = append(*ops, Operation{Op: "replace", Path: path, Value: to})
+	}
+}
+
+// object returns the names of the fields of the value if it is an object of
+// the data of results, a map or an *OrderedMap, in the order of the patches,
+// and the function returning their values.
+func object(value interface{}) ([]string, func(string) (interface{}, bool), bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		// the names are sorted so that the patches are deterministic
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, func(name string) (interface{}, bool) {
+			v, ok := value[name]
+			return v, ok
+		}, true
+	case *graphql.OrderedMap:
+		return value.Keys(), value.Get, true
+	}
+	return nil, nil, false
+}
+
+// escape escapes a reference token of a JSON Pointer.
+func escape(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
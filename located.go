@@ -42,6 +42,16 @@ func newLocatedError(err interface{}, nodes []ast.Node, path []interface{}) *gql
 	)
 }
 
+// newCodedError converts err into a *gqlerrors.Error with the given code,
+// unless err already has one.
+func newCodedError(err error, code string) *gqlerrors.Error {
+	e, ok := err.(*gqlerrors.Error)
+	if !ok {
+		e = gqlerrors.NewError(err.Error(), nil, "", nil, []int{}, err)
+	}
+	return gqlerrors.SetCode(e, code)
+}
+
 func FieldASTsToNodeASTs(fieldASTs []*ast.Field) []ast.Node {
 	nodes := []ast.Node{}
 	for _, fieldAST := range fieldASTs {
@@ -0,0 +1,34 @@
+package graphql
+
+// FieldMiddleware wraps the resolver of a field. It is called with the next
+// resolver of the chain, and returns the resolver to run in its place.
+//
+// A middleware can inspect and change the params before calling next, e.g.
+// to normalize the args or to check `p.Info` for authorization, change the
+// result or the error returned by next, or skip next altogether.
+//
+// Example:
+//
+//	func logging(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+//		return func(p graphql.ResolveParams) (interface{}, error) {
+//			start := time.Now()
+//			result, err := next(p)
+//			log.Printf("%v.%v took %v", p.Info.ParentType.Name(), p.Info.FieldName, time.Since(start))
+//			return result, err
+//		}
+//	}
+type FieldMiddleware func(next FieldResolveFn) FieldResolveFn
+
+// applyFieldMiddleware wraps resolveFn with the middleware of the schema,
+// then with the middleware of the parent type. The first middleware of each
+// list is the outermost one.
+func applyFieldMiddleware(schema Schema, parentType *Object, resolveFn FieldResolveFn) FieldResolveFn {
+	typeMiddleware := parentType.typeConfig.FieldMiddleware
+	for i := len(typeMiddleware) - 1; i >= 0; i-- {
+		resolveFn = typeMiddleware[i](resolveFn)
+	}
+	for i := len(schema.fieldMiddleware) - 1; i >= 0; i-- {
+		resolveFn = schema.fieldMiddleware[i](resolveFn)
+	}
+	return resolveFn
+}
@@ -0,0 +1,162 @@
+package graphql_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type middlewareUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func middlewareSchema(t *testing.T, schemaMiddleware []graphql.FieldMiddleware, typeMiddleware []graphql.FieldMiddleware) graphql.Schema {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"email": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+		FieldMiddleware: typeMiddleware,
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: userType,
+					Args: graphql.FieldConfigArgument{
+						"name": &graphql.ArgumentConfig{
+							Type: graphql.String,
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return middlewareUser{Name: p.Args["name"].(string), Email: "luke@rebels.org"}, nil
+					},
+				},
+			},
+		}),
+		FieldMiddleware: schemaMiddleware,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestFieldMiddleware_WrapsEveryFieldInOrder(t *testing.T) {
+	var calls []string
+	tracing := func(name string) graphql.FieldMiddleware {
+		return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				calls = append(calls, name+":"+p.Info.ParentType.Name()+"."+p.Info.FieldName)
+				return next(p)
+			}
+		}
+	}
+	schema := middlewareSchema(t,
+		[]graphql.FieldMiddleware{tracing("schema1"), tracing("schema2")},
+		[]graphql.FieldMiddleware{tracing("type")},
+	)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ user(name: "Luke") { name } }`,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := []string{
+		"schema1:Query.user",
+		"schema2:Query.user",
+		"schema1:User.name",
+		"schema2:User.name",
+		"type:User.name",
+	}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expected, calls))
+	}
+}
+
+func TestFieldMiddleware_CanChangeArgsAndResult(t *testing.T) {
+	normalizeArgs := func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if name, ok := p.Args["name"].(string); ok {
+				p.Args["name"] = strings.TrimSpace(name)
+			}
+			return next(p)
+		}
+	}
+	upperCase := func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			result, err := next(p)
+			if s, ok := result.(string); ok {
+				return strings.ToUpper(s), err
+			}
+			return result, err
+		}
+	}
+	schema := middlewareSchema(t,
+		[]graphql.FieldMiddleware{normalizeArgs},
+		[]graphql.FieldMiddleware{upperCase},
+	)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ user(name: "  Luke  ") { name } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"user": map[string]interface{}{
+				"name": "LUKE",
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestFieldMiddleware_CanSkipTheResolver(t *testing.T) {
+	schema := middlewareSchema(t, nil, nil)
+	schema.AddFieldMiddleware(func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			if p.Info.FieldName == "email" {
+				return nil, errors.New("not authorized")
+			}
+			return next(p)
+		}
+	})
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ user(name: "Luke") { name email } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"user": map[string]interface{}{
+				"name":  "Luke",
+				"email": nil,
+			},
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "not authorized",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 29}},
+				Path:       []interface{}{"user", "email"},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
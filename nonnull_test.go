@@ -130,6 +130,7 @@ func TestNonNull_NullsANullableFieldThatThrowsSynchronously(t *testing.T) {
 				Path: []interface{}{
 					"sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -168,6 +169,7 @@ func TestNonNull_NullsANullableFieldThatThrowsInAPromise(t *testing.T) {
 				Path: []interface{}{
 					"promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -209,6 +211,7 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANullableFieldThat
 					"nest",
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -250,6 +253,7 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 					"nest",
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -291,6 +295,7 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 					"promiseNest",
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -332,6 +337,7 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 					"promiseNest",
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -415,6 +421,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: syncError,
@@ -424,6 +431,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "nest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: syncError,
@@ -433,6 +441,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "promiseNest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: syncError,
@@ -442,6 +451,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: syncError,
@@ -451,6 +461,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "nest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: syncError,
@@ -460,6 +471,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "promiseNest", "sync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -469,6 +481,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -478,6 +491,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "nest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -487,6 +501,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"nest", "promiseNest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -496,6 +511,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -505,6 +521,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "nest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: promiseError,
@@ -514,6 +531,7 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				Path: []interface{}{
 					"promiseNest", "promiseNest", "promise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 		},
 	}
@@ -599,6 +617,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 					"nest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: nonNullSyncError,
@@ -609,6 +628,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 					"promiseNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: nonNullPromiseError,
@@ -619,6 +639,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 					"anotherNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
 				Message: nonNullPromiseError,
@@ -629,6 +650,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 					"anotherPromiseNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			}),
 		},
 	}
@@ -721,6 +743,7 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 					"nest",
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -760,6 +783,7 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 					"nest",
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -800,6 +824,7 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 					"promiseNest",
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -839,6 +864,7 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 					"promiseNest",
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -993,6 +1019,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 					"nest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 			{
 				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
@@ -1003,6 +1030,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 					"promiseNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 			{
 				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
@@ -1013,6 +1041,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 					"anotherNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 			{
 				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
@@ -1023,6 +1052,7 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 					"anotherPromiseNest", "nonNullNest", "nonNullPromiseNest", "nonNullNest",
 					"nonNullPromiseNest", "nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -1058,6 +1088,7 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldThrows(t *testing.T) {
 				Path: []interface{}{
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -1090,6 +1121,7 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldErrors(t *testing.T) {
 				Path: []interface{}{
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeResolverError},
 			},
 		},
 	}
@@ -1122,6 +1154,7 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldReturnsNull(t *testing.T)
 				Path: []interface{}{
 					"nonNullSync",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -1154,6 +1187,7 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldResolvesNull(t *testing.T
 				Path: []interface{}{
 					"nonNullPromise",
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeNonNullViolation},
 			},
 		},
 	}
@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// OrderedMap is a result object which keeps its fields in the order of the
+// selection set, as required by the spec. It is used for the objects of the
+// Result data when executing with OrderedResults, and marshals to a JSON
+// object with the fields in that order.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return newOrderedMap(0)
+}
+
+func newOrderedMap(size int) *OrderedMap {
+	return &OrderedMap{
+		keys:   make([]string, 0, size),
+		values: make(map[string]interface{}, size),
+	}
+}
+
+// Set sets the value of the field, appending the field if it is not set yet.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of the field, and whether it is set.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Keys returns the fields of the map, in order.
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// Len returns the number of fields of the map.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Map returns the `map[string]interface{}` view of the map, converting the
+// nested ordered maps as well. The order of the fields is lost.
+func (m *OrderedMap) Map() map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(m.keys))
+	for _, key := range m.keys {
+		result[key] = unorderedValue(m.values[key])
+	}
+	return result
+}
+
+// MarshalJSON implements json.Marshaler, writing the fields in order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func unorderedValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *OrderedMap:
+		if value == nil {
+			return nil
+		}
+		return value.Map()
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = unorderedValue(item)
+		}
+		return list
+	}
+	return value
+}
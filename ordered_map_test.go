@@ -0,0 +1,91 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestOrderedResults_KeepSelectionSetOrder(t *testing.T) {
+	query := `
+		query HeroNameAndFriendsQuery {
+			hero {
+				name
+				...HeroDetails
+				id
+				zFriends: friends {
+					name
+				}
+				aFriends: friends {
+					id
+					__typename
+				}
+			}
+		}
+		fragment HeroDetails on Character {
+			appearsIn
+			name
+		}
+	`
+	result := graphql.Do(graphql.Params{
+		Schema:         testutil.StarWarsSchema,
+		RequestString:  query,
+		OrderedResults: true,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"data":{"hero":{"name":"R2-D2","appearsIn":["NEWHOPE","EMPIRE","JEDI"],"id":"2001",` +
+		`"zFriends":[{"name":"Luke Skywalker"},{"name":"Han Solo"},{"name":"Leia Organa"}],` +
+		`"aFriends":[{"id":"1000","__typename":"Human"},{"id":"1002","__typename":"Human"},{"id":"1003","__typename":"Human"}]}}}`
+	if string(b) != expected {
+		t.Fatalf("Unexpected JSON:\n%v\nexpected:\n%v", string(b), expected)
+	}
+}
+
+func TestOrderedResults_MapView(t *testing.T) {
+	query := `{ hero { name friends { name } } }`
+	ordered := graphql.Do(graphql.Params{
+		Schema:         testutil.StarWarsSchema,
+		RequestString:  query,
+		OrderedResults: true,
+	})
+	unordered := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: query,
+	})
+	data, ok := ordered.Data.(*graphql.OrderedMap)
+	if !ok {
+		t.Fatalf("expected *graphql.OrderedMap data, got %T", ordered.Data)
+	}
+	if !reflect.DeepEqual(unordered.Data, data.Map()) {
+		t.Fatalf("Unexpected map view, Diff: %v", testutil.Diff(unordered.Data, data.Map()))
+	}
+}
+
+func TestOrderedMap_SetKeepsFirstPosition(t *testing.T) {
+	m := graphql.NewOrderedMap()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+	if !reflect.DeepEqual([]string{"b", "a"}, m.Keys()) {
+		t.Fatalf("Unexpected keys: %v", m.Keys())
+	}
+	if v, ok := m.Get("b"); !ok || v != 3 {
+		t.Fatalf("Unexpected value: %v", v)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != `{"b":3,"a":2}` {
+		t.Fatalf("Unexpected JSON: %v", string(b))
+	}
+}
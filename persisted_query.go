@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// PersistedQueryStore stores the queries of the automatic persisted queries
+// protocol, by the hex encoded SHA-256 hash of their text.
+//
+// With that protocol, a client first sends the hash of a query in the
+// `persistedQuery` extension of its request, without the query. If the hash
+// is unknown, the request fails with a PERSISTED_QUERY_NOT_FOUND error, and
+// the client sends it again along with the query, which is then stored.
+type PersistedQueryStore interface {
+	// Get returns the query of the hash, and whether it was found.
+	Get(ctx context.Context, hash string) (query string, found bool, err error)
+
+	// Set stores the query of the hash.
+	Set(ctx context.Context, hash string, query string) error
+}
+
+// LRUPersistedQueryStore is a PersistedQueryStore which keeps a bounded
+// number of queries in memory, evicting the least recently used ones first.
+type LRUPersistedQueryStore struct {
+	lru *lruCache
+}
+
+// NewLRUPersistedQueryStore returns a LRUPersistedQueryStore holding at most
+// size queries.
+func NewLRUPersistedQueryStore(size int) *LRUPersistedQueryStore {
+	return &LRUPersistedQueryStore{
+		lru: newLRUCache(size),
+	}
+}
+
+// Get implements PersistedQueryStore.
+func (s *LRUPersistedQueryStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	query, ok := s.lru.get(hash)
+	if !ok {
+		return "", false, nil
+	}
+	return query.(string), true, nil
+}
+
+// Set implements PersistedQueryStore.
+func (s *LRUPersistedQueryStore) Set(ctx context.Context, hash string, query string) error {
+	s.lru.add(hash, query)
+	return nil
+}
+
+// persistedQueryStore returns the store of the request, or the one of the
+// schema if the request does not set one.
+func persistedQueryStore(schema Schema, store PersistedQueryStore) PersistedQueryStore {
+	if store != nil {
+		return store
+	}
+	return schema.persistedQueryStore
+}
+
+// resolvePersistedQuery implements the automatic persisted queries protocol
+// for a request with the `persistedQuery` extension: it sets the request
+// string from the store, or stores it if it is sent along with its hash.
+// Errors of the store when storing the query are ignored, as the request can
+// still be executed.
+func resolvePersistedQuery(p *Params) error {
+	extension, ok := p.Extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if !isPersistedQueryVersion1(extension["version"]) {
+		return newCodedError(
+			errors.New("Unsupported persisted query version."),
+			gqlerrors.ErrCodePersistedQueryNotSupported,
+		)
+	}
+	hash, _ := extension["sha256Hash"].(string)
+	hash = strings.ToLower(hash)
+	store := persistedQueryStore(p.Schema, p.PersistedQueryStore)
+	if store == nil {
+		return newCodedError(
+			errors.New("PersistedQueryNotSupported"),
+			gqlerrors.ErrCodePersistedQueryNotSupported,
+		)
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if p.RequestString == "" {
+		query, found, err := store.Get(ctx, hash)
+		if err != nil {
+			return newCodedError(err, gqlerrors.ErrCodeInternalServerError)
+		}
+		if !found {
+			return newCodedError(
+				errors.New("PersistedQueryNotFound"),
+				gqlerrors.ErrCodePersistedQueryNotFound,
+			)
+		}
+		p.RequestString = query
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(p.RequestString))
+	if hex.EncodeToString(sum[:]) != hash {
+		return newCodedError(
+			errors.New("Provided sha256Hash does not match the query."),
+			gqlerrors.ErrCodePersistedQueryHashMismatch,
+		)
+	}
+	store.Set(ctx, hash, p.RequestString)
+	return nil
+}
+
+func isPersistedQueryVersion1(version interface{}) bool {
+	switch version := version.(type) {
+	case int:
+		return version == 1
+	case float64:
+		return version == 1
+	case json.Number:
+		return version.String() == "1"
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func persistedQueryExtensions(query string) map[string]interface{} {
+	sum := sha256.Sum256([]byte(query))
+	return map[string]interface{}{
+		"persistedQuery": map[string]interface{}{
+			"version":    1,
+			"sha256Hash": hex.EncodeToString(sum[:]),
+		},
+	}
+}
+
+func TestPersistedQuery_RegistersQueriesOnMiss(t *testing.T) {
+	query := `{ hero { name } }`
+	store := graphql.NewLRUPersistedQueryStore(10)
+	params := graphql.Params{
+		Schema:              testutil.StarWarsSchema,
+		Extensions:          persistedQueryExtensions(query),
+		PersistedQueryStore: store,
+	}
+
+	result := graphql.Do(params)
+	if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodePersistedQueryNotFound {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if result.Errors[0].Message != "PersistedQueryNotFound" {
+		t.Fatalf("Unexpected message: %v", result.Errors[0].Message)
+	}
+
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	params.RequestString = query
+	if result := graphql.Do(params); !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	params.RequestString = ""
+	if result := graphql.Do(params); !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestPersistedQuery_AcceptsDecodedJSONExtensions(t *testing.T) {
+	query := `{ hero { name } }`
+	body, _ := json.Marshal(persistedQueryExtensions(query))
+	var extensions map[string]interface{}
+	if err := json.Unmarshal(body, &extensions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := graphql.NewLRUPersistedQueryStore(10)
+	if err := store.Set(context.Background(), extensions["persistedQuery"].(map[string]interface{})["sha256Hash"].(string), query); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:               testutil.StarWarsSchema.QueryType(),
+		PersistedQueryStore: store,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:     schema,
+		Extensions: extensions,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestPersistedQuery_RejectsInvalidRequests(t *testing.T) {
+	query := `{ hero { name } }`
+	for name, test := range map[string]struct {
+		params graphql.Params
+		code   string
+	}{
+		"hash mismatch": {
+			params: graphql.Params{
+				RequestString:       `{ hero { id } }`,
+				Extensions:          persistedQueryExtensions(query),
+				PersistedQueryStore: graphql.NewLRUPersistedQueryStore(10),
+			},
+			code: gqlerrors.ErrCodePersistedQueryHashMismatch,
+		},
+		"no store": {
+			params: graphql.Params{
+				Extensions: persistedQueryExtensions(query),
+			},
+			code: gqlerrors.ErrCodePersistedQueryNotSupported,
+		},
+		"unsupported version": {
+			params: graphql.Params{
+				Extensions: map[string]interface{}{
+					"persistedQuery": map[string]interface{}{
+						"version":    2,
+						"sha256Hash": "abc",
+					},
+				},
+				PersistedQueryStore: graphql.NewLRUPersistedQueryStore(10),
+			},
+			code: gqlerrors.ErrCodePersistedQueryNotSupported,
+		},
+	} {
+		test.params.Schema = testutil.StarWarsSchema
+		result := graphql.Do(test.params)
+		if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != test.code {
+			t.Fatalf("Unexpected errors for %v: %v", name, result.Errors)
+		}
+	}
+}
+
+func TestPersistedQuery_ResolvesTheOperationOfRequests(t *testing.T) {
+	query := `query Hero { hero { name } }`
+	store := graphql.NewLRUPersistedQueryStore(10)
+	params := graphql.Params{
+		Schema:              testutil.StarWarsSchema,
+		Extensions:          persistedQueryExtensions(query),
+		PersistedQueryStore: store,
+	}
+	if _, result := graphql.ParseAndValidate(params); result == nil || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodePersistedQueryNotFound {
+		t.Fatalf("Unexpected result of an unknown query: %v", result)
+	}
+
+	sum := sha256.Sum256([]byte(query))
+	store.Set(context.Background(), hex.EncodeToString(sum[:]), query)
+	executeParams, result := graphql.ParseAndValidate(params)
+	if result != nil {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	operation := executeParams.Operation()
+	if operation == nil || operation.Operation != "query" || operation.Name.Value != "Hero" {
+		t.Fatalf("Unexpected operation: %v", operation)
+	}
+
+	params.AllowedOperationTypes = []string{"mutation"}
+	result = graphql.Do(params)
+	if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeOperationNotAllowed || !result.IsRequestError() {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	params.AllowedOperationTypes = []string{"query"}
+	if result := graphql.Do(params); len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
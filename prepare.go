@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// PreparedOperation is an operation which has been parsed and validated
+// once, and can then be executed many times with different variables.
+// It is safe for concurrent use.
+type PreparedOperation struct {
+	schema    Schema
+	document  *ast.Document
+	operation *ast.OperationDefinition
+	fragments map[string]ast.Definition
+
+	// rootType and rootFields are the root field plan of the operation. The
+	// root fields are nil if they depend on the variables, through the
+	// @skip and @include directives.
+	rootType   *Object
+	rootFields *collectedFields
+}
+
+// PrepareError is the error returned by Prepare for an invalid request. Its
+// Errors are the errors of the Result that Do would return.
+type PrepareError struct {
+	Errors []gqlerrors.FormattedError
+}
+
+func (e *PrepareError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Prepare parses and validates the request, and selects the operation to
+// execute, so that it can be executed many times without repeating that
+// work. The extensions of the schema are run for the parsing and the
+// validation by Prepare, and for the execution by each Execute.
+//
+// The returned error is a *PrepareError if the request is invalid.
+func Prepare(schema Schema, requestString string, operationName string) (*PreparedOperation, error) {
+	p := Params{
+		Schema:        schema,
+		RequestString: requestString,
+		OperationName: operationName,
+	}
+	AST, result := parseAndValidate(&p)
+	if result != nil {
+		return nil, &PrepareError{Errors: result.Errors}
+	}
+	operation, fragments, err := selectOperation(AST, operationName)
+	if err != nil {
+		return nil, &PrepareError{Errors: presentErrors(nil, schema.errorPresenter, err)}
+	}
+	rootType, err := getOperationRootType(schema, operation)
+	if err != nil {
+		return nil, &PrepareError{Errors: presentErrors(nil, schema.errorPresenter, err)}
+	}
+
+	op := &PreparedOperation{
+		schema:    schema,
+		document:  AST,
+		operation: operation,
+		fragments: fragments,
+		rootType:  rootType,
+	}
+	if !hasConditionalSelections(operation.GetSelectionSet(), fragments, map[string]bool{}) {
+		op.rootFields = collectFields(collectFieldsParams{
+			ExeContext: &executionContext{
+				Schema:    schema,
+				Fragments: fragments,
+			},
+			RuntimeType:  rootType,
+			SelectionSet: operation.GetSelectionSet(),
+		})
+	}
+	return op, nil
+}
+
+// Execute executes the operation with the given variables and root value.
+func (op *PreparedOperation) Execute(ctx context.Context, variables map[string]interface{}, root interface{}) *Result {
+	return Execute(op.executeParams(ctx, variables, root))
+}
+
+// ExecuteToWriter executes the operation like Execute, but writes the JSON
+// encoded result to w. See ExecuteToWriter.
+func (op *PreparedOperation) ExecuteToWriter(ctx context.Context, variables map[string]interface{}, root interface{}, w io.Writer) error {
+	return ExecuteToWriter(op.executeParams(ctx, variables, root), w)
+}
+
+func (op *PreparedOperation) executeParams(ctx context.Context, variables map[string]interface{}, root interface{}) ExecuteParams {
+	operationName := ""
+	if op.operation.Name != nil {
+		operationName = op.operation.Name.Value
+	}
+	return ExecuteParams{
+		Schema:        op.schema,
+		Root:          root,
+		AST:           op.document,
+		OperationName: operationName,
+		Args:          variables,
+		Context:       ctx,
+		prepared:      op,
+	}
+}
+
+// Operation returns the operation to execute.
+func (op *PreparedOperation) Operation() *ast.OperationDefinition {
+	return op.operation
+}
+
+// hasConditionalSelections returns whether the fields collected from the
+// selection set depend on the @skip or @include directives.
+func hasConditionalSelections(selectionSet *ast.SelectionSet, fragments map[string]ast.Definition, visitedFragmentNames map[string]bool) bool {
+	if selectionSet == nil {
+		return false
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if hasConditionalDirective(selection.Directives) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if hasConditionalDirective(selection.Directives) ||
+				hasConditionalSelections(selection.SelectionSet, fragments, visitedFragmentNames) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if hasConditionalDirective(selection.Directives) {
+				return true
+			}
+			if selection.Name == nil || visitedFragmentNames[selection.Name.Value] {
+				continue
+			}
+			visitedFragmentNames[selection.Name.Value] = true
+			if fragment, ok := fragments[selection.Name.Value].(*ast.FragmentDefinition); ok &&
+				hasConditionalSelections(fragment.SelectionSet, fragments, visitedFragmentNames) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasConditionalDirective(directives []*ast.Directive) bool {
+	for _, directive := range directives {
+		if directive == nil || directive.Name == nil {
+			continue
+		}
+		if directive.Name.Value == SkipDirective.Name || directive.Name.Value == IncludeDirective.Name {
+			return true
+		}
+	}
+	return false
+}
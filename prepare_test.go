@@ -0,0 +1,130 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestPrepare_ExecutesWithDifferentVariables(t *testing.T) {
+	op, err := graphql.Prepare(testutil.StarWarsSchema, `
+		query A { hero { name } }
+		query HumanQuery($id: String!) {
+			human(id: $id) {
+				...HumanName
+			}
+		}
+		fragment HumanName on Human {
+			name
+		}
+	`, "HumanQuery")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for id, name := range map[string]string{"1000": "Luke Skywalker", "1002": "Han Solo"} {
+		wg.Add(1)
+		go func(id, name string) {
+			defer wg.Done()
+			result := op.Execute(context.Background(), map[string]interface{}{"id": id}, nil)
+			expected := &graphql.Result{
+				Data: map[string]interface{}{
+					"human": map[string]interface{}{
+						"name": name,
+					},
+				},
+			}
+			if !reflect.DeepEqual(expected, result) {
+				t.Errorf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+			}
+		}(id, name)
+	}
+	wg.Wait()
+
+	result := op.Execute(context.Background(), nil, nil)
+	if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeBadUserInput {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestPrepare_EvaluatesDirectivesOnEachExecution(t *testing.T) {
+	op, err := graphql.Prepare(testutil.StarWarsSchema, `
+		query ($withId: Boolean!) {
+			hero {
+				name
+			}
+			... on Query @include(if: $withId) {
+				hero {
+					id
+				}
+			}
+		}
+	`, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for withID, expected := range map[bool]interface{}{
+		true:  map[string]interface{}{"hero": map[string]interface{}{"id": "2001", "name": "R2-D2"}},
+		false: map[string]interface{}{"hero": map[string]interface{}{"name": "R2-D2"}},
+	} {
+		result := op.Execute(context.Background(), map[string]interface{}{"withId": withID}, nil)
+		if !reflect.DeepEqual(expected, result.Data) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
+		}
+	}
+}
+
+func TestPrepare_ExecuteToWriter(t *testing.T) {
+	op, err := graphql.Prepare(testutil.StarWarsSchema, `{ hero { name } }`, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := op.ExecuteToWriter(context.Background(), nil, nil, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, _ := json.Marshal(op.Execute(context.Background(), nil, nil))
+	if buf.String() != string(expected) {
+		t.Fatalf("Unexpected result: %v, expected: %v", buf.String(), string(expected))
+	}
+}
+
+func TestPrepare_ReturnsTheErrorsOfInvalidRequests(t *testing.T) {
+	for query, code := range map[string]string{
+		`{ hero { name `:       gqlerrors.ErrCodeParseFailed,
+		`{ hero { unknown } }`: gqlerrors.ErrCodeValidationFailed,
+		`query A { hero { name } } query B { hero { id } }`: gqlerrors.ErrCodeOperationResolutionFailure,
+	} {
+		_, err := graphql.Prepare(testutil.StarWarsSchema, query, "")
+		var prepareErr *graphql.PrepareError
+		if !errors.As(err, &prepareErr) {
+			t.Fatalf("expected a *graphql.PrepareError for %q, got %v", query, err)
+		}
+		if len(prepareErr.Errors) != 1 || gqlerrors.Code(prepareErr.Errors[0]) != code {
+			t.Fatalf("Unexpected errors for %q: %v", query, prepareErr.Errors)
+		}
+	}
+}
+
+func BenchmarkPreparedOperation_Execute(b *testing.B) {
+	schema := testutil.StarWarsSchema
+	op, err := graphql.Prepare(schema, `{ hero { name friends { name appearsIn } } }`, "")
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := op.Execute(context.Background(), nil, nil)
+		if len(result.Errors) > 0 {
+			b.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+		}
+	}
+}
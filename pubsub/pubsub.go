@@ -0,0 +1,336 @@
+// Package pubsub implements a topic-based publish/subscribe broker, whose
+// subscriptions are the source streams of the subscriptions of a schema:
+//
+//	broker := pubsub.NewMemory(nil)
+//
+//	"commentAdded": &graphql.Field{
+//		Type: commentType,
+//		Args: graphql.FieldConfigArgument{
+//			"postID": &graphql.ArgumentConfig{Type: graphql.ID},
+//		},
+//		Subscribe: pubsub.Filter(
+//			pubsub.SubscribeFn(broker, "comments"),
+//			func(p graphql.ResolveParams, payload interface{}) bool {
+//				return payload.(*Comment).PostID == p.Args["postID"]
+//			},
+//		),
+//		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+//			return p.Source, nil
+//		},
+//	},
+//
+//	broker.Publish("comments", comment)
+//
+// The PubSub interface can be implemented by other brokers, e.g. of the
+// messages of a Redis or NATS server, shared by several processes.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// PubSub is a broker of the payloads published on topics.
+type PubSub interface {
+	// Publish sends the payload to the subscribers of the topic.
+	Publish(topic string, payload interface{}) error
+
+	// Subscribe returns the stream of the payloads published on the topics
+	// until the context is done or the stream is closed.
+	Subscribe(ctx context.Context, topics ...string) (graphql.SourceStream, error)
+}
+
+// SubscribeFn returns a Subscribe function of a field, subscribing to the
+// topics of the broker.
+func SubscribeFn(ps PubSub, topics ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return ps.Subscribe(p.Context, topics...)
+	}
+}
+
+// FilterFn reports whether the payload is sent to the subscriber of the
+// params, e.g. by comparing it to their arguments or to a value of their
+// context.
+type FilterFn func(p graphql.ResolveParams, payload interface{}) bool
+
+// Filter returns a Subscribe function of the source stream of subscribe,
+// whose events are only the payloads accepted by the filter for the
+// subscriber.
+func Filter(subscribe graphql.FieldResolveFn, filter FilterFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := subscribe(p)
+		if err != nil {
+			return nil, err
+		}
+		stream, ok := result.(graphql.SourceStream)
+		if !ok {
+			return nil, fmt.Errorf("pubsub: the subscribe function returned %T, not a graphql.SourceStream", result)
+		}
+		return &filteredStream{stream: stream, params: p, filter: filter}, nil
+	}
+}
+
+// filteredStream is a source stream of the events of another one accepted
+// by a filter.
+type filteredStream struct {
+	stream graphql.SourceStream
+	params graphql.ResolveParams
+	filter FilterFn
+}
+
+func (s *filteredStream) Next(ctx context.Context) (interface{}, error) {
+	for {
+		event, err := s.stream.Next(ctx)
+		if err != nil || s.filter(s.params, event) {
+			return event, err
+		}
+	}
+}
+
+func (s *filteredStream) Close() error {
+	return s.stream.Close()
+}
+
+// Policy is the behavior of the Publish calls for the subscribers whose
+// buffer is full, i.e. consuming the payloads slower than they are
+// published.
+type Policy int
+
+const (
+	// DropNewest drops the payloads published while the buffer of the
+	// subscriber is full.
+	DropNewest Policy = iota
+
+	// DropOldest drops the oldest payload of the buffer of the subscriber to
+	// make room for the published one.
+	DropOldest
+
+	// Block blocks the Publish calls until the subscriber receives the
+	// payload or its subscription ends.
+	Block
+)
+
+// DefaultBufferSize is the default number of payloads buffered for each
+// subscriber.
+const DefaultBufferSize = 16
+
+// ErrClosed is returned by the Publish and Subscribe calls of a closed
+// broker.
+var ErrClosed = errors.New("pubsub: closed")
+
+// MemoryConfig is the configuration of a Memory broker.
+type MemoryConfig struct {
+	// BufferSize is the number of payloads buffered for each subscriber,
+	// DefaultBufferSize if 0.
+	BufferSize int
+
+	// Policy is the behavior for the subscribers whose buffer is full.
+	Policy Policy
+
+	// DropFn, if set, is called with the topic of each Publish call and the
+	// payload it dropped for a subscriber, if any.
+	DropFn func(topic string, payload interface{})
+}
+
+// Memory is a PubSub broker of the payloads published in the process.
+type Memory struct {
+	config MemoryConfig
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscriber]struct{}
+	closed      bool
+}
+
+var _ PubSub = (*Memory)(nil)
+
+// NewMemory returns a Memory broker of the configuration, which can be nil
+// for the default one.
+func NewMemory(config *MemoryConfig) *Memory {
+	m := &Memory{subscribers: map[string]map[*subscriber]struct{}{}}
+	if config != nil {
+		m.config = *config
+	}
+	if m.config.BufferSize <= 0 {
+		m.config.BufferSize = DefaultBufferSize
+	}
+	return m
+}
+
+// Publish sends the payload to the subscribers of the topic, in the order
+// of the Publish calls for each of them.
+func (m *Memory) Publish(topic string, payload interface{}) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ErrClosed
+	}
+	subscribers := make([]*subscriber, 0, len(m.subscribers[topic]))
+	for s := range m.subscribers[topic] {
+		subscribers = append(subscribers, s)
+	}
+	m.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if dropped, ok := s.send(payload, m.config.Policy); ok && m.config.DropFn != nil {
+			m.config.DropFn(topic, dropped)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the stream of the payloads published on the topics, a
+// payload published on several of them being received once per topic.
+func (m *Memory) Subscribe(ctx context.Context, topics ...string) (graphql.SourceStream, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("pubsub: no topic to subscribe to")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s := &subscriber{
+		memory: m,
+		topics: topics,
+		events: make(chan interface{}, m.config.BufferSize),
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrClosed
+	}
+	for _, topic := range topics {
+		if m.subscribers[topic] == nil {
+			m.subscribers[topic] = map[*subscriber]struct{}{}
+		}
+		m.subscribers[topic][s] = struct{}{}
+	}
+	m.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// Close ends the subscriptions of the broker, whose Publish and Subscribe
+// calls then fail.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	var subscribers []*subscriber
+	for _, topicSubscribers := range m.subscribers {
+		for s := range topicSubscribers {
+			subscribers = append(subscribers, s)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.Close()
+	}
+	return nil
+}
+
+// unsubscribe removes the subscriber from its topics.
+func (m *Memory) unsubscribe(s *subscriber) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, topic := range s.topics {
+		delete(m.subscribers[topic], s)
+		if len(m.subscribers[topic]) == 0 {
+			delete(m.subscribers, topic)
+		}
+	}
+}
+
+// subscriber is the source stream of a subscription to a Memory broker.
+// Its events channel is never closed, so that the Publish calls can not
+// send on a closed channel.
+type subscriber struct {
+	memory *Memory
+	topics []string
+	events chan interface{}
+
+	// sendMu serializes the sends, so that the ones of the DropOldest policy
+	// are atomic
+	sendMu    sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// send sends the payload according to the policy, returning the payload
+// dropped instead, if any.
+func (s *subscriber) send(payload interface{}, policy Policy) (dropped interface{}, ok bool) {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	select {
+	case <-s.done:
+		return nil, false
+	default:
+	}
+	switch policy {
+	case Block:
+		select {
+		case s.events <- payload:
+		case <-s.done:
+		}
+		return nil, false
+	case DropOldest:
+		for {
+			select {
+			case s.events <- payload:
+				return dropped, ok
+			default:
+			}
+			// the buffer can be emptied by the subscriber in the meantime
+			select {
+			case dropped = <-s.events:
+				ok = true
+			default:
+			}
+		}
+	default:
+		select {
+		case s.events <- payload:
+			return nil, false
+		default:
+			return payload, true
+		}
+	}
+}
+
+// Next returns the next payload, or io.EOF once the subscription ended.
+func (s *subscriber) Next(ctx context.Context) (interface{}, error) {
+	select {
+	case <-s.done:
+		return nil, io.EOF
+	default:
+	}
+	select {
+	case event := <-s.events:
+		return event, nil
+	case <-s.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close ends the subscription.
+func (s *subscriber) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.memory.unsubscribe(s)
+	})
+	return nil
+}
@@ -0,0 +1,193 @@
+package pubsub_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/pubsub"
+)
+
+func next(t *testing.T, stream graphql.SourceStream) interface{} {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	event, err := stream.Next(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return event
+}
+
+func TestMemory_PublishesToTheSubscribersOfTheTopics(t *testing.T) {
+	broker := pubsub.NewMemory(nil)
+	a, _ := broker.Subscribe(context.Background(), "a")
+	ab, _ := broker.Subscribe(context.Background(), "a", "b")
+
+	broker.Publish("a", 1)
+	broker.Publish("b", 2)
+	broker.Publish("c", 3)
+	if event := next(t, a); event != 1 {
+		t.Fatalf("Unexpected event: %v", event)
+	}
+	if events := []interface{}{next(t, ab), next(t, ab)}; !reflect.DeepEqual([]interface{}{1, 2}, events) {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+
+	a.Close()
+	if _, err := a.Next(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF once closed, got %v", err)
+	}
+	broker.Close()
+	if _, err := ab.Next(context.Background()); err != io.EOF {
+		t.Fatalf("Expected io.EOF once the broker is closed, got %v", err)
+	}
+	if err := broker.Publish("a", 4); err != pubsub.ErrClosed {
+		t.Fatalf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestMemory_EndsTheSubscriptionsOnceTheirContextIsDone(t *testing.T) {
+	broker := pubsub.NewMemory(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, _ := broker.Subscribe(ctx, "a")
+	cancel()
+
+	done := make(chan error)
+	go func() {
+		_, err := stream.Next(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Fatalf("Expected io.EOF, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the subscription to end")
+	}
+}
+
+func TestMemory_Policies(t *testing.T) {
+	tests := []struct {
+		policy   pubsub.Policy
+		expected []interface{}
+		dropped  []interface{}
+	}{
+		{pubsub.DropNewest, []interface{}{1, 2}, []interface{}{3}},
+		{pubsub.DropOldest, []interface{}{2, 3}, []interface{}{1}},
+	}
+	for _, test := range tests {
+		var dropped []interface{}
+		broker := pubsub.NewMemory(&pubsub.MemoryConfig{
+			BufferSize: 2,
+			Policy:     test.policy,
+			DropFn: func(topic string, payload interface{}) {
+				dropped = append(dropped, payload)
+			},
+		})
+		stream, _ := broker.Subscribe(context.Background(), "a")
+		for i := 1; i <= 3; i++ {
+			broker.Publish("a", i)
+		}
+		if events := []interface{}{next(t, stream), next(t, stream)}; !reflect.DeepEqual(test.expected, events) {
+			t.Fatalf("Unexpected events for policy %v: %v", test.policy, events)
+		}
+		if !reflect.DeepEqual(test.dropped, dropped) {
+			t.Fatalf("Unexpected dropped payloads for policy %v: %v", test.policy, dropped)
+		}
+	}
+
+	broker := pubsub.NewMemory(&pubsub.MemoryConfig{BufferSize: 1, Policy: pubsub.Block})
+	stream, _ := broker.Subscribe(context.Background(), "a")
+	broker.Publish("a", 1)
+	published := make(chan struct{})
+	go func() {
+		broker.Publish("a", 2)
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatalf("Expected Publish to block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if events := []interface{}{next(t, stream), next(t, stream)}; !reflect.DeepEqual([]interface{}{1, 2}, events) {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+	<-published
+}
+
+func TestFilter_SubscribesToTheAcceptedPayloads(t *testing.T) {
+	type comment struct {
+		PostID  string
+		Content string
+	}
+	broker := pubsub.NewMemory(nil)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"ok": &graphql.Field{Type: graphql.Boolean},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"commentAdded": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"postID": &graphql.ArgumentConfig{Type: graphql.ID},
+					},
+					Subscribe: pubsub.Filter(
+						pubsub.SubscribeFn(broker, "comments"),
+						func(p graphql.ResolveParams, payload interface{}) bool {
+							return payload.(comment).PostID == p.Args["postID"]
+						},
+					),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(comment).Content, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { commentAdded(postID: "1") }`,
+		Context:       ctx,
+	})
+	// the subscription to the broker is made asynchronously, so the payloads
+	// are published until a result is received
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+			broker.Publish("comments", comment{PostID: "2", Content: "other"})
+			broker.Publish("comments", comment{PostID: "1", Content: "hello"})
+		}
+	}()
+	select {
+	case result := <-results:
+		expected := map[string]interface{}{"commentAdded": "hello"}
+		if len(result.Errors) != 0 || !reflect.DeepEqual(expected, result.Data) {
+			t.Fatalf("Unexpected result: %v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a result")
+	}
+	cancel()
+	<-published
+}
@@ -33,6 +33,7 @@ var SpecifiedRules = []ValidationRuleFn{
 	PossibleFragmentSpreadsRule,
 	ProvidedNonNullArgumentsRule,
 	ScalarLeafsRule,
+	SingleFieldSubscriptionsRule,
 	UniqueArgumentNamesRule,
 	UniqueFragmentNamesRule,
 	UniqueInputFieldNamesRule,
@@ -1381,6 +1382,89 @@ func ScalarLeafsRule(context *ValidationContext) *ValidationRuleInstance {
 	}
 }
 
+// SingleFieldSubscriptionsRule Subscriptions must only include one field
+//
+// A GraphQL subscription is valid only if it contains a single root field,
+// which is not an introspection field. The directives of the fields are not
+// evaluated.
+func SingleFieldSubscriptionsRule(context *ValidationContext) *ValidationRuleInstance {
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.OperationDefinition: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					node, ok := p.Node.(*ast.OperationDefinition)
+					if !ok || node == nil || node.Operation != ast.OperationTypeSubscription {
+						return visitor.ActionNoChange, nil
+					}
+					subscription := "Anonymous Subscription"
+					if node.Name != nil && node.Name.Value != "" {
+						subscription = fmt.Sprintf(`Subscription "%v"`, node.Name.Value)
+					}
+					var responseNames []string
+					fields := map[string][]ast.Node{}
+					collectRootFields(context, node.SelectionSet, map[string]bool{}, func(field *ast.Field) {
+						responseName := getFieldEntryKey(field)
+						if _, ok := fields[responseName]; !ok {
+							responseNames = append(responseNames, responseName)
+						}
+						fields[responseName] = append(fields[responseName], field)
+					})
+					if len(responseNames) > 1 {
+						var extraFields []ast.Node
+						for _, responseName := range responseNames[1:] {
+							extraFields = append(extraFields, fields[responseName]...)
+						}
+						reportError(
+							context,
+							fmt.Sprintf(`%v must select only one top level field.`, subscription),
+							extraFields,
+						)
+					}
+					for _, responseName := range responseNames {
+						if strings.HasPrefix(fields[responseName][0].(*ast.Field).Name.Value, "__") {
+							reportError(
+								context,
+								fmt.Sprintf(`%v must not select an introspection top level field.`, subscription),
+								fields[responseName],
+							)
+						}
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
+// collectRootFields calls fn with the fields of the selection set, and of
+// its fragments.
+func collectRootFields(context *ValidationContext, selectionSet *ast.SelectionSet, visitedFragments map[string]bool, fn func(*ast.Field)) {
+	if selectionSet == nil {
+		return
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name != nil {
+				fn(selection)
+			}
+		case *ast.InlineFragment:
+			collectRootFields(context, selection.SelectionSet, visitedFragments, fn)
+		case *ast.FragmentSpread:
+			if selection.Name == nil || visitedFragments[selection.Name.Value] {
+				continue
+			}
+			visitedFragments[selection.Name.Value] = true
+			if fragment := context.Fragment(selection.Name.Value); fragment != nil {
+				collectRootFields(context, fragment.SelectionSet, visitedFragments, fn)
+			}
+		}
+	}
+}
+
 // UniqueArgumentNamesRule Unique argument names
 //
 // A GraphQL field or directive is only valid if all supplied arguments are
@@ -1797,7 +1881,11 @@ func isValidLiteralValue(ttype Input, valueAST ast.Value) (bool, []string) {
 		}
 		return (len(messagesReduce) == 0), messagesReduce
 	case *Scalar:
-		if isNullish(ttype.ParseLiteral(valueAST)) {
+		parsed, err := ttype.ParseLiteralWithError(valueAST)
+		if err != nil {
+			return false, []string{fmt.Sprintf(`Expected type "%v", found %v; %v`, ttype.Name(), printer.Print(valueAST), err.Error())}
+		}
+		if isNullish(parsed) {
 			return false, []string{fmt.Sprintf(`Expected type "%v", found %v.`, ttype.Name(), printer.Print(valueAST))}
 		}
 	case *Enum:
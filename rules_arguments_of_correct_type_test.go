@@ -167,7 +167,7 @@ func TestValidate_ArgValuesOfCorrectType_InvalidIntValues_BigIntIntoInt(t *testi
         `,
 		[]gqlerrors.FormattedError{
 			testutil.RuleError(
-				"Argument \"intArg\" has invalid value 829384293849283498239482938.\nExpected type \"Int\", found 829384293849283498239482938.",
+				"Argument \"intArg\" has invalid value 829384293849283498239482938.\nExpected type \"Int\", found 829384293849283498239482938; Int cannot represent non 32-bit signed integer value",
 				4, 33,
 			),
 		})
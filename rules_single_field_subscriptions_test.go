@@ -0,0 +1,71 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestValidate_SingleFieldSubscriptions_ValidSubscription(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.SingleFieldSubscriptionsRule, `
+      subscription ImportantEmails {
+        importantEmails
+      }
+    `)
+}
+func TestValidate_SingleFieldSubscriptions_ValidSubscriptionWithFragments(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.SingleFieldSubscriptionsRule, `
+      subscription sub {
+        ...newMessageFields
+        ... { newMessage { body } }
+      }
+      fragment newMessageFields on SubscriptionRoot {
+        newMessage {
+          sender
+        }
+      }
+    `)
+}
+func TestValidate_SingleFieldSubscriptions_FailsWithMoreThanOneRootField(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.SingleFieldSubscriptionsRule, `
+      subscription ImportantEmails {
+        importantEmails
+        notImportantEmails
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Subscription "ImportantEmails" must select only one top level field.`, 4, 9),
+	})
+}
+func TestValidate_SingleFieldSubscriptions_FailsWithMoreThanOneRootFieldInFragments(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.SingleFieldSubscriptionsRule, `
+      subscription {
+        importantEmails
+        ...moreFields
+      }
+      fragment moreFields on SubscriptionRoot {
+        importantEmails
+        spamEmails
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Anonymous Subscription must select only one top level field.`, 8, 9),
+	})
+}
+func TestValidate_SingleFieldSubscriptions_FailsWithAnIntrospectionField(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.SingleFieldSubscriptionsRule, `
+      subscription ImportantEmails {
+        __typename
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Subscription "ImportantEmails" must not select an introspection top level field.`, 3, 9),
+	})
+}
+func TestValidate_SingleFieldSubscriptions_IgnoresQueries(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.SingleFieldSubscriptionsRule, `
+      query {
+        dog { name }
+        human { name }
+      }
+    `)
+}
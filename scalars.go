@@ -1,152 +1,126 @@
 package graphql
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"reflect"
+	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 )
 
+var (
+	// intPattern and floatPattern match the strings holding JSON numbers.
+	intPattern   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	floatPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+)
+
+// inspectValue formats a value for the coercion errors, quoting strings.
+func inspectValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case json.Number:
+		return string(value)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 // As per the GraphQL Spec, Integers are only treated as valid when a valid
 // 32-bit signed integer, providing the broadest support across platforms.
 //
 // n.b. JavaScript's integers are safe between -(2^53 - 1) and 2^53 - 1 because
 // they are internally represented as IEEE 754 doubles.
-func coerceInt(value interface{}) interface{} {
+//
+// coerceInt serializes values of any integer or float type, json.Number
+// values, booleans, strings holding integers and driver.Valuer values. The
+// fractional values of float types are truncated, whereas the fractional
+// strings and json.Number values and the out of range values are errors
+// rather than null.
+func coerceInt(value interface{}) (interface{}, error) {
 	switch value := value.(type) {
 	case bool:
-		if value == true {
-			return 1
+		if value {
+			return 1, nil
 		}
-		return 0
-	case *bool:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case int:
-		if value < int(math.MinInt32) || value > int(math.MaxInt32) {
-			return nil
-		}
-		return value
-	case *int:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case int8:
-		return int(value)
-	case *int8:
-		if value == nil {
-			return nil
-		}
-		return int(*value)
-	case int16:
-		return int(value)
-	case *int16:
-		if value == nil {
-			return nil
-		}
-		return int(*value)
-	case int32:
-		return int(value)
-	case *int32:
-		if value == nil {
-			return nil
-		}
-		return int(*value)
-	case int64:
-		if value < int64(math.MinInt32) || value > int64(math.MaxInt32) {
-			return nil
-		}
-		return int(value)
-	case *int64:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case uint:
-		if value > math.MaxInt32 {
-			return nil
-		}
-		return int(value)
-	case *uint:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case uint8:
-		return int(value)
-	case *uint8:
-		if value == nil {
-			return nil
-		}
-		return int(*value)
-	case uint16:
-		return int(value)
-	case *uint16:
-		if value == nil {
-			return nil
-		}
-		return int(*value)
-	case uint32:
-		if value > uint32(math.MaxInt32) {
-			return nil
-		}
-		return int(value)
-	case *uint32:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case uint64:
-		if value > uint64(math.MaxInt32) {
-			return nil
-		}
-		return int(value)
-	case *uint64:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case float32:
-		if value < float32(math.MinInt32) || value > float32(math.MaxInt32) {
-			return nil
-		}
-		return int(value)
-	case *float32:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
-	case float64:
-		if value < float64(math.MinInt32) || value > float64(math.MaxInt32) {
-			return nil
-		}
-		return int(value)
-	case *float64:
-		if value == nil {
-			return nil
-		}
-		return coerceInt(*value)
+		return 0, nil
 	case string:
-		val, err := strconv.ParseFloat(value, 0)
-		if err != nil {
-			return nil
+		if !intPattern.MatchString(value) {
+			return nil, fmt.Errorf("Int cannot represent non-integer value: %v", inspectValue(value))
 		}
-		return coerceInt(val)
-	case *string:
+		return numberToInt(json.Number(value))
+	}
+	if value, ok := driverValue(value); ok {
 		if value == nil {
-			return nil
+			return nil, nil
 		}
-		return coerceInt(*value)
+		return coerceInt(value)
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return coerceInt(v.Elem().Interface())
+	case reflect.Float32, reflect.Float64:
+		return numberToInt(math.Trunc(v.Float()))
 	}
+	return numberToInt(value)
+}
 
-	// If the value cannot be transformed into an int, return nil instead of '0'
-	// to denote 'no integer found'
-	return nil
+// parseIntValue accepts numbers only, i.e. values of any integer or float
+// type and json.Number values, as the variables decoded from JSON.
+func parseIntValue(value interface{}) (interface{}, error) {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return parseIntValue(v.Elem().Interface())
+	}
+	return numberToInt(value)
+}
+
+// numberToInt converts a number to an int, failing for fractional and out of
+// range values, and for values which are not numbers.
+func numberToInt(value interface{}) (interface{}, error) {
+	if number, ok := value.(json.Number); ok {
+		if i, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			return numberToInt(i)
+		}
+		f, err := number.Float64()
+		if err != nil && !math.IsInf(f, 0) {
+			return nil, fmt.Errorf("Int cannot represent non-integer value: %v", inspectValue(value))
+		}
+		return numberToInt(f)
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < math.MinInt32 || v.Int() > math.MaxInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", inspectValue(value))
+		}
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", inspectValue(value))
+		}
+		return int(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || f != math.Trunc(f) {
+			return nil, fmt.Errorf("Int cannot represent non-integer value: %v", inspectValue(value))
+		}
+		if f < math.MinInt32 || f > math.MaxInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %v", inspectValue(value))
+		}
+		return int(f), nil
+	}
+	return nil, fmt.Errorf("Int cannot represent non-integer value: %v", inspectValue(value))
 }
 
 // Int is the GraphQL Integer type definition.
@@ -154,131 +128,91 @@ var Int = NewScalar(ScalarConfig{
 	Name: "Int",
 	Description: "The `Int` scalar type represents non-fractional signed whole numeric " +
 		"values. Int can represent values between -(2^31) and 2^31 - 1. ",
-	Serialize:  coerceInt,
-	ParseValue: coerceInt,
-	ParseLiteral: func(valueAST ast.Value) interface{} {
+	SerializeWithError:  coerceInt,
+	ParseValueWithError: parseIntValue,
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
 		switch valueAST := valueAST.(type) {
 		case *ast.IntValue:
-			if intValue, err := strconv.Atoi(valueAST.Value); err == nil {
-				return intValue
+			intValue, err := strconv.ParseInt(valueAST.Value, 10, 32)
+			if err != nil {
+				// the validation reports the literal along with the error
+				return nil, errors.New("Int cannot represent non 32-bit signed integer value")
 			}
+			return int(intValue), nil
 		}
-		return nil
+		return nil, nil
 	},
 })
 
-func coerceFloat(value interface{}) interface{} {
+// coerceFloat serializes values of any integer or float type, json.Number
+// values, booleans, strings holding numbers and driver.Valuer values. NaN and
+// infinite values, which JSON can not represent, are errors.
+func coerceFloat(value interface{}) (interface{}, error) {
 	switch value := value.(type) {
 	case bool:
-		if value == true {
-			return 1.0
+		if value {
+			return 1.0, nil
 		}
-		return 0.0
-	case *bool:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case int:
-		return float64(value)
-	case *int:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case int8:
-		return float64(value)
-	case *int8:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case int16:
-		return float64(value)
-	case *int16:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case int32:
-		return float64(value)
-	case *int32:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case int64:
-		return float64(value)
-	case *int64:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case uint:
-		return float64(value)
-	case *uint:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case uint8:
-		return float64(value)
-	case *uint8:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case uint16:
-		return float64(value)
-	case *uint16:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case uint32:
-		return float64(value)
-	case *uint32:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
-	case uint64:
-		return float64(value)
-	case *uint64:
-		if value == nil {
-			return nil
+		return 0.0, nil
+	case string:
+		if !floatPattern.MatchString(value) {
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %v", inspectValue(value))
 		}
-		return coerceFloat(*value)
-	case float32:
-		return value
-	case *float32:
+		return numberToFloat(json.Number(value))
+	}
+	if value, ok := driverValue(value); ok {
 		if value == nil {
-			return nil
+			return nil, nil
 		}
-		return coerceFloat(*value)
-	case float64:
-		return value
-	case *float64:
-		if value == nil {
-			return nil
+		return coerceFloat(value)
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
 		}
-		return coerceFloat(*value)
-	case string:
-		val, err := strconv.ParseFloat(value, 0)
+		return coerceFloat(v.Elem().Interface())
+	}
+	return numberToFloat(value)
+}
+
+// parseFloatValue accepts numbers only, i.e. values of any integer or float
+// type and json.Number values, as the variables decoded from JSON.
+func parseFloatValue(value interface{}) (interface{}, error) {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		return parseFloatValue(v.Elem().Interface())
+	}
+	return numberToFloat(value)
+}
+
+// numberToFloat converts a number to a float64, or keeps a float32, failing
+// for NaN and infinite values, and for values which are not numbers.
+func numberToFloat(value interface{}) (interface{}, error) {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
 		if err != nil {
-			return nil
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %v", inspectValue(value))
 		}
-		return val
-	case *string:
-		if value == nil {
-			return nil
-		}
-		return coerceFloat(*value)
+		return numberToFloat(f)
 	}
-
-	// If the value cannot be transformed into an float, return nil instead of '0.0'
-	// to denote 'no float found'
-	return nil
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+			return nil, fmt.Errorf("Float cannot represent non numeric value: %v", inspectValue(value))
+		}
+		if f, ok := value.(float32); ok {
+			return f, nil
+		}
+		return v.Float(), nil
+	}
+	return nil, fmt.Errorf("Float cannot represent non numeric value: %v", inspectValue(value))
 }
 
 // Float is the GraphQL float type definition.
@@ -287,8 +221,8 @@ var Float = NewScalar(ScalarConfig{
 	Description: "The `Float` scalar type represents signed double-precision fractional " +
 		"values as specified by " +
 		"[IEEE 754](http://en.wikipedia.org/wiki/IEEE_floating_point). ",
-	Serialize:  coerceFloat,
-	ParseValue: coerceFloat,
+	SerializeWithError:  coerceFloat,
+	ParseValueWithError: parseFloatValue,
 	ParseLiteral: func(valueAST ast.Value) interface{} {
 		switch valueAST := valueAST.(type) {
 		case *ast.FloatValue:
@@ -311,6 +245,12 @@ func coerceString(value interface{}) interface{} {
 		}
 		return *v
 	}
+	if value, ok := driverValue(value); ok {
+		if value == nil {
+			return nil
+		}
+		return coerceString(value)
+	}
 	return fmt.Sprintf("%v", value)
 }
 
@@ -472,6 +412,12 @@ func coerceBool(value interface{}) interface{} {
 		}
 		return coerceBool(*value)
 	}
+	if value, ok := driverValue(value); ok {
+		if value == nil {
+			return nil
+		}
+		return coerceBool(value)
+	}
 	return false
 }
 
@@ -490,6 +436,52 @@ var Boolean = NewScalar(ScalarConfig{
 	},
 })
 
+// serializeID formats integers without exponent, so that large IDs
+// decoded as float64 values keep their digits, and other values as strings.
+func serializeID(value interface{}) interface{} {
+	if id, ok := numberToID(value); ok {
+		return id
+	}
+	return coerceString(value)
+}
+
+// parseIDValue accepts strings and integers, e.g. json.Number values.
+func parseIDValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case *string:
+		if value == nil {
+			return nil, nil
+		}
+		return *value, nil
+	}
+	if id, ok := numberToID(value); ok {
+		return id, nil
+	}
+	return nil, fmt.Errorf("ID cannot represent value: %v", inspectValue(value))
+}
+
+// numberToID formats an integer of any integer or float type, or a
+// json.Number holding an integer, as a string.
+func numberToID(value interface{}) (string, bool) {
+	if number, ok := value.(json.Number); ok {
+		return string(number), intPattern.MatchString(string(number))
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'f', -1, 64), true
+		}
+	}
+	return "", false
+}
+
 // ID is the GraphQL id type definition
 var ID = NewScalar(ScalarConfig{
 	Name: "ID",
@@ -498,8 +490,8 @@ var ID = NewScalar(ScalarConfig{
 		"response as a String; however, it is not intended to be human-readable. " +
 		"When expected as an input type, any string (such as `\"4\"`) or integer " +
 		"(such as `4`) input value will be accepted as an ID.",
-	Serialize:  coerceString,
-	ParseValue: coerceString,
+	Serialize:           serializeID,
+	ParseValueWithError: parseIDValue,
 	ParseLiteral: func(valueAST ast.Value) interface{} {
 		switch valueAST := valueAST.(type) {
 		case *ast.IntValue:
@@ -526,6 +518,9 @@ func serializeDateTime(value interface{}) interface{} {
 		}
 		return serializeDateTime(*value)
 	default:
+		if value, ok := driverValue(value); ok && value != nil {
+			return serializeDateTime(value)
+		}
 		return nil
 	}
 }
@@ -0,0 +1,81 @@
+package scalars
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// JSON is an arbitrary JSON value. Values are serialized as they are, except
+// json.RawMessage values which are decoded first. Literals are parsed to
+// map[string]interface{}, []interface{}, string, bool, int64 and float64
+// values, and can not contain variables.
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "JSON",
+	Description:    "The `JSON` scalar type represents arbitrary JSON values.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc8259",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		raw, ok := value.(json.RawMessage)
+		if !ok {
+			return value, nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, cannotRepresent("JSON", string(raw), err.Error())
+		}
+		return decoded, nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return value, nil
+	},
+	ParseLiteralWithError: parseJSONLiteral,
+})
+
+func parseJSONLiteral(valueAST ast.Value) (interface{}, error) {
+	switch valueAST := valueAST.(type) {
+	case *ast.StringValue:
+		return valueAST.Value, nil
+	case *ast.BooleanValue:
+		return valueAST.Value, nil
+	case *ast.IntValue:
+		if value, err := strconv.ParseInt(valueAST.Value, 10, 64); err == nil {
+			return value, nil
+		}
+		value, err := strconv.ParseFloat(valueAST.Value, 64)
+		if err != nil {
+			return nil, cannotRepresent("JSON", valueAST, "invalid number")
+		}
+		return value, nil
+	case *ast.FloatValue:
+		value, err := strconv.ParseFloat(valueAST.Value, 64)
+		if err != nil {
+			return nil, cannotRepresent("JSON", valueAST, "invalid number")
+		}
+		return value, nil
+	case *ast.EnumValue:
+		return valueAST.Value, nil
+	case *ast.ListValue:
+		values := make([]interface{}, 0, len(valueAST.Values))
+		for _, itemAST := range valueAST.Values {
+			value, err := parseJSONLiteral(itemAST)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case *ast.ObjectValue:
+		values := make(map[string]interface{}, len(valueAST.Fields))
+		for _, field := range valueAST.Fields {
+			value, err := parseJSONLiteral(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			values[field.Name.Value] = value
+		}
+		return values, nil
+	}
+	return nil, cannotRepresent("JSON", valueAST, "variables are not supported in JSON literals")
+}
@@ -0,0 +1,258 @@
+package scalars
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// maxSafeFloatInteger is the largest integer up to which every integer is
+// exactly represented by a float64, and so by a number decoded from JSON.
+const maxSafeFloatInteger = 1 << 53
+
+// Int64 is a signed 64-bit integer, serialized as a JSON number. Values are
+// parsed to int64. Numbers decoded as float64 beyond 2^53 are rejected as
+// they may have lost precision: such values are to be sent as strings or
+// json.Number values.
+var Int64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Int64",
+	Description:    "The `Int64` scalar type represents signed 64-bit integers.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc8259#section-6",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		return toInt64("Int64", value)
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return toInt64("Int64", value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		switch valueAST := valueAST.(type) {
+		case *ast.IntValue:
+			return toInt64("Int64", valueAST.Value)
+		case *ast.StringValue:
+			return toInt64("Int64", valueAST.Value)
+		}
+		return nil, cannotRepresent("Int64", valueAST, "expected an integer")
+	},
+})
+
+func toInt64(scalar string, value interface{}) (int64, error) {
+	if s, ok := stringValue(value); ok {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, cannotRepresent(scalar, s, "expected a 64-bit integer")
+		}
+		return i, nil
+	}
+	switch value := value.(type) {
+	case json.Number:
+		return toInt64(scalar, value.String())
+	case float32:
+		return toInt64(scalar, float64(value))
+	case float64:
+		if value != math.Trunc(value) || math.Abs(value) > maxSafeFloatInteger {
+			return 0, cannotRepresent(scalar, value, "expected an integer of at most 2^53, larger ones are to be sent as strings")
+		}
+		return int64(value), nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, cannotRepresent(scalar, value, "overflows a 64-bit integer")
+		}
+		return int64(v.Uint()), nil
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return toInt64(scalar, v.Elem().Interface())
+		}
+	}
+	return 0, unsupportedType(scalar, value)
+}
+
+// BigInt is an integer of arbitrary size, serialized as a JSON string of its
+// decimal representation. Values are parsed to *big.Int.
+var BigInt = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "BigInt",
+	Description:    "The `BigInt` scalar type represents integers of arbitrary size, as decimal strings.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc8259#section-6",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		i, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return i.String(), nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return toBigInt(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		switch valueAST := valueAST.(type) {
+		case *ast.IntValue:
+			return toBigInt(valueAST.Value)
+		case *ast.StringValue:
+			return toBigInt(valueAST.Value)
+		}
+		return nil, cannotRepresent("BigInt", valueAST, "expected an integer")
+	},
+})
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	if s, ok := stringValue(value); ok {
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, cannotRepresent("BigInt", s, "expected an integer")
+		}
+		return i, nil
+	}
+	switch value := value.(type) {
+	case *big.Int:
+		if value == nil {
+			return nil, unsupportedType("BigInt", value)
+		}
+		return value, nil
+	case big.Int:
+		return &value, nil
+	case json.Number:
+		return toBigInt(value.String())
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	}
+	i, err := toInt64("BigInt", value)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(i), nil
+}
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// Decimal is an exact decimal number, serialized as a JSON string. Values
+// are parsed to *big.Rat. Serialized *big.Rat values must have a finite
+// decimal representation.
+var Decimal = graphql.NewScalar(graphql.ScalarConfig{
+	Name:               "Decimal",
+	Description:        "The `Decimal` scalar type represents exact decimal numbers, as strings.",
+	SpecifiedByURL:     "https://www.rfc-editor.org/rfc/rfc8259#section-6",
+	SerializeWithError: serializeDecimal,
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		switch value := value.(type) {
+		case float32:
+			return parseDecimal(strconv.FormatFloat(float64(value), 'f', -1, 32))
+		case float64:
+			return parseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+		case json.Number:
+			return parseDecimal(value.String())
+		}
+		if s, ok := stringValue(value); ok {
+			return parseDecimal(s)
+		}
+		i, err := toBigInt(value)
+		if err != nil {
+			return nil, unsupportedType("Decimal", value)
+		}
+		return new(big.Rat).SetInt(i), nil
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		switch valueAST := valueAST.(type) {
+		case *ast.IntValue:
+			return parseDecimal(valueAST.Value)
+		case *ast.FloatValue:
+			return parseDecimal(valueAST.Value)
+		case *ast.StringValue:
+			return parseDecimal(valueAST.Value)
+		}
+		return nil, cannotRepresent("Decimal", valueAST, "expected a decimal number")
+	},
+})
+
+func parseDecimal(s string) (*big.Rat, error) {
+	if !decimalPattern.MatchString(s) {
+		return nil, cannotRepresent("Decimal", s, "expected a decimal number")
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, cannotRepresent("Decimal", s, "expected a decimal number")
+	}
+	return r, nil
+}
+
+func serializeDecimal(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case *big.Rat:
+		if value == nil {
+			return nil, unsupportedType("Decimal", value)
+		}
+		return ratDecimalString(value)
+	case big.Rat:
+		return ratDecimalString(&value)
+	case *big.Float:
+		if value == nil || value.IsInf() {
+			return nil, cannotRepresent("Decimal", value, "expected a finite number")
+		}
+		return value.Text('f', -1), nil
+	case float32:
+		if math.IsInf(float64(value), 0) || math.IsNaN(float64(value)) {
+			return nil, cannotRepresent("Decimal", value, "expected a finite number")
+		}
+		return strconv.FormatFloat(float64(value), 'f', -1, 32), nil
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, cannotRepresent("Decimal", value, "expected a finite number")
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case json.Number:
+		r, err := parseDecimal(value.String())
+		if err != nil {
+			return nil, err
+		}
+		return ratDecimalString(r)
+	}
+	if s, ok := stringValue(value); ok {
+		r, err := parseDecimal(s)
+		if err != nil {
+			return nil, err
+		}
+		return ratDecimalString(r)
+	}
+	i, err := toBigInt(value)
+	if err != nil {
+		return nil, unsupportedType("Decimal", value)
+	}
+	return i.String(), nil
+}
+
+// ratDecimalString returns the exact decimal representation of r, which
+// exists only if its denominator has no other prime factors than 2 and 5.
+func ratDecimalString(r *big.Rat) (string, error) {
+	denom := new(big.Int).Set(r.Denom())
+	two, five, zero := big.NewInt(2), big.NewInt(5), new(big.Int)
+	var twos, fives int
+	mod := new(big.Int)
+	for mod.Mod(denom, two).Cmp(zero) == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for mod.Mod(denom, five).Cmp(zero) == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return "", cannotRepresent("Decimal", r.String(), "no finite decimal representation")
+	}
+	prec := twos
+	if fives > prec {
+		prec = fives
+	}
+	return r.FloatString(prec), nil
+}
@@ -0,0 +1,53 @@
+// Package scalars provides custom scalar types commonly needed by GraphQL
+// schemas, in addition to the built-in Int, Float, String, Boolean and ID.
+//
+// Each scalar validates the values it serializes and parses strictly, and
+// reports why a value is rejected, both in the errors of the fields it is
+// serialized for and in the errors of the arguments and variables it is
+// parsed from.
+package scalars
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// cannotRepresent returns the error of a value a scalar can not represent.
+func cannotRepresent(scalar string, value interface{}, reason string) error {
+	if valueAST, ok := value.(ast.Value); ok {
+		return fmt.Errorf("%v cannot represent %v: %v", scalar, printer.Print(valueAST), reason)
+	}
+	if value, ok := value.(string); ok {
+		return fmt.Errorf("%v cannot represent %q: %v", scalar, value, reason)
+	}
+	return fmt.Errorf("%v cannot represent %v (%T): %v", scalar, value, value, reason)
+}
+
+// unsupportedType returns the error of a value of a Go type a scalar does
+// not support.
+func unsupportedType(scalar string, value interface{}) error {
+	return cannotRepresent(scalar, value, "unsupported type")
+}
+
+// stringLiteral returns the value of a string literal.
+func stringLiteral(scalar string, valueAST ast.Value) (string, error) {
+	if valueAST, ok := valueAST.(*ast.StringValue); ok {
+		return valueAST.Value, nil
+	}
+	return "", cannotRepresent(scalar, valueAST, "expected a string")
+}
+
+// stringValue returns the value of a string, or of a pointer to a string.
+func stringValue(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case *string:
+		if value != nil {
+			return *value, true
+		}
+	}
+	return "", false
+}
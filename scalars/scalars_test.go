@@ -0,0 +1,181 @@
+package scalars_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/scalars"
+)
+
+type scalarTest struct {
+	scalar *graphql.Scalar
+	in     interface{}
+	want   interface{}
+	// err is a part of the expected error message, if any.
+	err string
+}
+
+func runScalarTests(t *testing.T, name string, fn func(*graphql.Scalar, interface{}) (interface{}, error), tests []scalarTest) {
+	for _, test := range tests {
+		got, err := fn(test.scalar, test.in)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v %v of %#v: expected error %q, got %v, %v", test.scalar, name, test.in, test.err, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v %v of %#v: unexpected error: %v", test.scalar, name, test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.want, got) {
+			t.Errorf("%v %v of %#v: got %#v, want %#v", test.scalar, name, test.in, got, test.want)
+		}
+	}
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestScalars_Serialize(t *testing.T) {
+	date := time.Date(2020, 2, 29, 13, 4, 5, 600000000, time.UTC)
+	rat, _ := new(big.Rat).SetString("12.50")
+	runScalarTests(t, "Serialize", (*graphql.Scalar).SerializeWithError, []scalarTest{
+		{scalar: scalars.JSON, in: map[string]interface{}{"a": 1}, want: map[string]interface{}{"a": 1}},
+		{scalar: scalars.JSON, in: json.RawMessage(`{"a":[true]}`), want: map[string]interface{}{"a": []interface{}{true}}},
+		{scalar: scalars.JSON, in: json.RawMessage(`{`), err: "JSON cannot represent"},
+		{scalar: scalars.Int64, in: int32(-5), want: int64(-5)},
+		{scalar: scalars.Int64, in: uint64(1) << 63, err: "overflows a 64-bit integer"},
+		{scalar: scalars.Int64, in: "9007199254740993", want: int64(9007199254740993)},
+		{scalar: scalars.Int64, in: 1.5, err: "expected an integer"},
+		{scalar: scalars.BigInt, in: new(big.Int).Lsh(big.NewInt(1), 70), want: "1180591620717411303424"},
+		{scalar: scalars.BigInt, in: uint64(1) << 63, want: "9223372036854775808"},
+		{scalar: scalars.Decimal, in: rat, want: "12.5"},
+		{scalar: scalars.Decimal, in: big.NewRat(1, 3), err: "no finite decimal representation"},
+		{scalar: scalars.Decimal, in: 0.1, want: "0.1"},
+		{scalar: scalars.Decimal, in: "1e3", want: "1000"},
+		{scalar: scalars.Decimal, in: "1,5", err: "expected a decimal number"},
+		{scalar: scalars.Date, in: date, want: "2020-02-29"},
+		{scalar: scalars.Date, in: "2021-02-29", err: "expected a date of the form YYYY-MM-DD"},
+		{scalar: scalars.Time, in: date, want: "2020-02-29T13:04:05.6Z"},
+		{scalar: scalars.Time, in: "2020-02-29 13:04:05", err: "expected a RFC 3339 date-time"},
+		{scalar: scalars.Duration, in: 90*time.Minute + 500*time.Millisecond, want: "PT1H30M0.5S"},
+		{scalar: scalars.Duration, in: -time.Second, want: "-PT1S"},
+		{scalar: scalars.Duration, in: time.Duration(0), want: "PT0S"},
+		{scalar: scalars.Duration, in: "P1D", want: "PT24H"},
+		{scalar: scalars.UUID, in: [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, want: "123e4567-e89b-12d3-a456-426614174000"},
+		{scalar: scalars.UUID, in: "123E4567-E89B-12D3-A456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000"},
+		{scalar: scalars.UUID, in: "123e4567", err: "expected a UUID"},
+		{scalar: scalars.URL, in: mustParseURL("https://golang.org/doc"), want: "https://golang.org/doc"},
+		{scalar: scalars.URL, in: "/doc", err: "expected an absolute URL"},
+		{scalar: scalars.Email, in: "gopher@golang.org", want: "gopher@golang.org"},
+		{scalar: scalars.Email, in: "Gopher <gopher@golang.org>", err: "expected an email address"},
+		{scalar: scalars.Base64, in: []byte("hello"), want: "aGVsbG8="},
+		{scalar: scalars.Base64, in: "aGVsbG8", err: "expected standard padded base64"},
+		{scalar: scalars.Void, in: "anything", want: nil},
+		{scalar: scalars.UUID, in: 42, err: "unsupported type"},
+	})
+}
+
+func TestScalars_ParseValue(t *testing.T) {
+	runScalarTests(t, "ParseValue", (*graphql.Scalar).ParseValueWithError, []scalarTest{
+		{scalar: scalars.JSON, in: []interface{}{"a", 1.0}, want: []interface{}{"a", 1.0}},
+		{scalar: scalars.Int64, in: 42.0, want: int64(42)},
+		{scalar: scalars.Int64, in: 9007199254740994.0, err: "larger ones are to be sent as strings"},
+		{scalar: scalars.Int64, in: json.Number("9223372036854775807"), want: int64(9223372036854775807)},
+		{scalar: scalars.Int64, in: "9223372036854775808", err: "expected a 64-bit integer"},
+		{scalar: scalars.BigInt, in: "-123456789012345678901234567890", want: func() *big.Int {
+			i, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+			return i
+		}()},
+		{scalar: scalars.BigInt, in: "12a", err: "expected an integer"},
+		{scalar: scalars.Decimal, in: "19.99", want: big.NewRat(1999, 100)},
+		{scalar: scalars.Decimal, in: 0.5, want: big.NewRat(1, 2)},
+		{scalar: scalars.Decimal, in: "NaN", err: "expected a decimal number"},
+		{scalar: scalars.Date, in: "2020-02-29", want: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{scalar: scalars.Time, in: "2020-02-29T13:04:05+02:00", want: time.Date(2020, 2, 29, 13, 4, 5, 0, time.FixedZone("", 2*60*60))},
+		{scalar: scalars.Duration, in: "P1W2DT3H4M5.25S", want: 9*24*time.Hour + 3*time.Hour + 4*time.Minute + 5250*time.Millisecond},
+		{scalar: scalars.Duration, in: "-PT0,5S", want: -500 * time.Millisecond},
+		{scalar: scalars.Duration, in: "P1M", err: "expected an ISO 8601 duration"},
+		{scalar: scalars.Duration, in: "PT", err: "expected an ISO 8601 duration"},
+		{scalar: scalars.Duration, in: "PT9999999999999H", err: "overflows a duration"},
+		{scalar: scalars.UUID, in: "123e4567-e89b-12d3-a456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000"},
+		{scalar: scalars.URL, in: "mailto:gopher@golang.org", want: mustParseURL("mailto:gopher@golang.org")},
+		{scalar: scalars.URL, in: "https://", err: "expected an absolute URL"},
+		{scalar: scalars.Email, in: "gopher", err: "expected an email address"},
+		{scalar: scalars.Base64, in: "aGVsbG8=", want: []byte("hello")},
+		{scalar: scalars.Void, in: "anything", err: "expected null"},
+	})
+}
+
+func TestScalars_ParseLiteral(t *testing.T) {
+	parseLiteral := func(scalar *graphql.Scalar, value interface{}) (interface{}, error) {
+		return scalar.ParseLiteralWithError(value.(ast.Value))
+	}
+	runScalarTests(t, "ParseLiteral", parseLiteral, []scalarTest{
+		{
+			scalar: scalars.JSON,
+			in: &ast.ObjectValue{Fields: []*ast.ObjectField{
+				{Name: &ast.Name{Value: "a"}, Value: &ast.ListValue{Values: []ast.Value{
+					&ast.IntValue{Value: "1"},
+					&ast.FloatValue{Value: "1.5"},
+					&ast.BooleanValue{Value: true},
+					&ast.EnumValue{Value: "RED"},
+				}}},
+			}},
+			want: map[string]interface{}{"a": []interface{}{int64(1), 1.5, true, "RED"}},
+		},
+		{scalar: scalars.JSON, in: &ast.Variable{Name: &ast.Name{Value: "v"}}, err: "variables are not supported"},
+		{scalar: scalars.Int64, in: &ast.IntValue{Value: "9223372036854775807"}, want: int64(9223372036854775807)},
+		{scalar: scalars.Int64, in: &ast.FloatValue{Value: "1.5"}, err: "expected an integer"},
+		{scalar: scalars.BigInt, in: &ast.IntValue{Value: "92233720368547758070"}, want: func() *big.Int {
+			i, _ := new(big.Int).SetString("92233720368547758070", 10)
+			return i
+		}()},
+		{scalar: scalars.Decimal, in: &ast.FloatValue{Value: "0.1"}, want: big.NewRat(1, 10)},
+		{scalar: scalars.Date, in: &ast.StringValue{Value: "2020-02-29"}, want: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{scalar: scalars.Date, in: &ast.IntValue{Value: "20200229"}, err: "expected a string"},
+		{scalar: scalars.Email, in: &ast.StringValue{Value: "gopher@golang.org"}, want: "gopher@golang.org"},
+		{scalar: scalars.Void, in: &ast.StringValue{Value: ""}, err: "expected null"},
+	})
+}
+
+func TestScalars_SpecifiedByURLIsIntrospected(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"id": &graphql.Field{Type: scalars.UUID},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			uuid: __type(name: "UUID") { specifiedByURL }
+			string: __type(name: "String") { specifiedByURL }
+		}`,
+	})
+	expected := map[string]interface{}{
+		"uuid":   map[string]interface{}{"specifiedByURL": "https://www.rfc-editor.org/rfc/rfc4122"},
+		"string": map[string]interface{}{"specifiedByURL": nil},
+	}
+	if len(result.Errors) != 0 || !reflect.DeepEqual(expected, result.Data) {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
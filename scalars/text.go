@@ -0,0 +1,196 @@
+package scalars
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// UUID is a RFC 4122 UUID, serialized as a lowercase string, e.g.
+// `123e4567-e89b-12d3-a456-426614174000`. [16]byte values are serialized as
+// well. Values are parsed to lowercase strings.
+var UUID = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "UUID",
+	Description:    "The `UUID` scalar type represents RFC 4122 UUIDs.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc4122",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		if value, ok := value.([16]byte); ok {
+			s := hex.EncodeToString(value[:])
+			return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+		}
+		return parseUUID(value)
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseUUID(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("UUID", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseUUID(s)
+	},
+})
+
+func parseUUID(value interface{}) (string, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return "", unsupportedType("UUID", value)
+	}
+	if !uuidPattern.MatchString(s) {
+		return "", cannotRepresent("UUID", s, "expected a UUID of the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+	}
+	return strings.ToLower(s), nil
+}
+
+// URL is an absolute URL, serialized as a string. Values are parsed to
+// *url.URL.
+var URL = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "URL",
+	Description:    "The `URL` scalar type represents absolute URLs.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc3986",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		switch value := value.(type) {
+		case *url.URL:
+			if value != nil && value.IsAbs() {
+				return value.String(), nil
+			}
+			return nil, cannotRepresent("URL", value, "expected an absolute URL")
+		case url.URL:
+			if value.IsAbs() {
+				return value.String(), nil
+			}
+			return nil, cannotRepresent("URL", value.String(), "expected an absolute URL")
+		}
+		u, err := parseURL(value)
+		if err != nil {
+			return nil, err
+		}
+		return u.String(), nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseURL(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("URL", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseURL(s)
+	},
+})
+
+func parseURL(value interface{}) (*url.URL, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil, unsupportedType("URL", value)
+	}
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() || (u.Opaque == "" && u.Host == "") {
+		return nil, cannotRepresent("URL", s, "expected an absolute URL")
+	}
+	return u, nil
+}
+
+// Email is an email address without display name, e.g. `gopher@golang.org`,
+// serialized as a string. Values are parsed to strings.
+var Email = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Email",
+	Description:    "The `Email` scalar type represents email addresses.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		return parseEmail(value)
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseEmail(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("Email", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseEmail(s)
+	},
+})
+
+func parseEmail(value interface{}) (string, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return "", unsupportedType("Email", value)
+	}
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Name != "" || address.Address != s {
+		return "", cannotRepresent("Email", s, "expected an email address")
+	}
+	return s, nil
+}
+
+// Base64 is binary data, serialized as a standard, padded, base64 string.
+// []byte values are encoded, and strings are expected to be encoded already.
+// Values are parsed to []byte.
+var Base64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Base64",
+	Description:    "The `Base64` scalar type represents binary data, as base64 strings.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc4648#section-4",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		if value, ok := value.([]byte); ok {
+			return base64.StdEncoding.EncodeToString(value), nil
+		}
+		s, ok := stringValue(value)
+		if !ok {
+			return nil, unsupportedType("Base64", value)
+		}
+		if _, err := parseBase64(s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseBase64(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("Base64", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseBase64(s)
+	},
+})
+
+func parseBase64(value interface{}) ([]byte, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil, unsupportedType("Base64", value)
+	}
+	b, err := base64.StdEncoding.Strict().DecodeString(s)
+	if err != nil {
+		return nil, cannotRepresent("Base64", s, "expected standard padded base64")
+	}
+	return b, nil
+}
+
+// Void is the type of fields whose value is meaningless, e.g. of mutations
+// returning nothing: every value is serialized as null, and no value can be
+// parsed.
+var Void = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Void",
+	Description:    "The `Void` scalar type represents the absence of a value, and is always null.",
+	SpecifiedByURL: "https://spec.graphql.org/October2021/#sec-Null-Value",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		return nil, nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return nil, cannotRepresent("Void", value, "expected null")
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		return nil, cannotRepresent("Void", valueAST, "expected null")
+	},
+})
@@ -0,0 +1,211 @@
+package scalars
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date, serialized as a RFC 3339 full-date string, e.g.
+// `2006-01-02`. Values are parsed to time.Time values at midnight UTC.
+var Date = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Date",
+	Description:    "The `Date` scalar type represents calendar dates, as RFC 3339 full-date strings.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc3339#section-5.6",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		switch value := value.(type) {
+		case time.Time:
+			return value.Format(dateLayout), nil
+		case *time.Time:
+			if value != nil {
+				return value.Format(dateLayout), nil
+			}
+		}
+		t, err := parseDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return t.Format(dateLayout), nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseDate(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("Date", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseDate(s)
+	},
+})
+
+func parseDate(value interface{}) (time.Time, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return time.Time{}, unsupportedType("Date", value)
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, cannotRepresent("Date", s, "expected a date of the form YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+// Time is an instant, serialized as a RFC 3339 date-time string with
+// nanoseconds, e.g. `2006-01-02T15:04:05.999999999Z07:00`. Values are parsed
+// to time.Time values, keeping their offset.
+var Time = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Time",
+	Description:    "The `Time` scalar type represents instants, as RFC 3339 date-time strings.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc3339#section-5.6",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		switch value := value.(type) {
+		case time.Time:
+			return value.Format(time.RFC3339Nano), nil
+		case *time.Time:
+			if value != nil {
+				return value.Format(time.RFC3339Nano), nil
+			}
+		}
+		t, err := parseTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return t.Format(time.RFC3339Nano), nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseTime(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("Time", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseTime(s)
+	},
+})
+
+func parseTime(value interface{}) (time.Time, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return time.Time{}, unsupportedType("Time", value)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, cannotRepresent("Time", s, "expected a RFC 3339 date-time")
+	}
+	return t, nil
+}
+
+// durationPattern matches the ISO 8601 durations without years and months,
+// whose length is not fixed.
+var durationPattern = regexp.MustCompile(`^([-+])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:[.,](\d{1,9}))?S)?)?$`)
+
+// Duration is a length of time, serialized as an ISO 8601 duration string,
+// e.g. `PT1H30M`. Durations in years and months, whose length is not fixed,
+// are rejected, and a day is 24 hours. Values are parsed to time.Duration.
+var Duration = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Duration",
+	Description:    "The `Duration` scalar type represents lengths of time, as ISO 8601 duration strings.",
+	SpecifiedByURL: "https://en.wikipedia.org/wiki/ISO_8601#Durations",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		switch value := value.(type) {
+		case time.Duration:
+			return formatDuration(value), nil
+		case *time.Duration:
+			if value != nil {
+				return formatDuration(*value), nil
+			}
+		}
+		d, err := parseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		return formatDuration(d), nil
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		return parseDuration(value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		s, err := stringLiteral("Duration", valueAST)
+		if err != nil {
+			return nil, err
+		}
+		return parseDuration(s)
+	},
+})
+
+func parseDuration(value interface{}) (time.Duration, error) {
+	s, ok := stringValue(value)
+	if !ok {
+		return 0, unsupportedType("Duration", value)
+	}
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return 0, cannotRepresent("Duration", s, "expected an ISO 8601 duration in weeks, days, hours, minutes and seconds")
+	}
+	var total int64
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(m[i+2], 10, 64)
+		if err != nil || n > math.MaxInt64/int64(unit) || total > math.MaxInt64-n*int64(unit) {
+			return 0, cannotRepresent("Duration", s, "overflows a duration")
+		}
+		total += n * int64(unit)
+	}
+	if fraction := m[7]; fraction != "" {
+		nanos, _ := strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if total > math.MaxInt64-nanos {
+			return 0, cannotRepresent("Duration", s, "overflows a duration")
+		}
+		total += nanos
+	}
+	if m[1] == "-" {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	// the magnitude of the duration, which can not be negated for the
+	// smallest duration
+	magnitude := uint64(d)
+	if d < 0 {
+		b.WriteString("-")
+		magnitude = uint64(-(d + 1)) + 1
+	}
+	b.WriteString("PT")
+	hours := magnitude / uint64(time.Hour)
+	magnitude -= hours * uint64(time.Hour)
+	minutes := magnitude / uint64(time.Minute)
+	magnitude -= minutes * uint64(time.Minute)
+	seconds := magnitude / uint64(time.Second)
+	nanos := magnitude - seconds*uint64(time.Second)
+	if hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+	if seconds > 0 || nanos > 0 {
+		b.WriteString(strconv.FormatUint(seconds, 10))
+		if nanos > 0 {
+			b.WriteString("." + strings.TrimRight(strconv.FormatUint(nanos+uint64(time.Second), 10)[1:], "0"))
+		}
+		b.WriteString("S")
+	}
+	return b.String()
+}
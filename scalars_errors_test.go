@@ -0,0 +1,190 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func parseEmail(value string) (interface{}, error) {
+	if !strings.Contains(value, "@") {
+		return nil, errors.New(`an email address must contain "@"`)
+	}
+	return value, nil
+}
+
+var emailScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name: "Email",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		email, _ := value.(string)
+		return parseEmail(email)
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		email, ok := value.(string)
+		if !ok {
+			return nil, errors.New("an email address must be a string")
+		}
+		return parseEmail(email)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		email, ok := valueAST.(*ast.StringValue)
+		if !ok {
+			return nil, errors.New("an email address must be a string")
+		}
+		return parseEmail(email.Value)
+	},
+})
+
+func emailSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: emailScalar,
+					Args: graphql.FieldConfigArgument{
+						"email": &graphql.ArgumentConfig{Type: emailScalar},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["email"], nil
+					},
+				},
+				"stored": &graphql.Field{
+					Type: emailScalar,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "nobody", nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestScalarWithErrors_ParsesValidValues(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:         emailSchema(t),
+		RequestString:  `query ($email: Email) { a: echo(email: "a@b.c") b: echo(email: $email) }`,
+		VariableValues: map[string]interface{}{"email": "d@e.f"},
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{"a": "a@b.c", "b": "d@e.f"}
+	if data, _ := result.Data.(map[string]interface{}); data["a"] != expected["a"] || data["b"] != expected["b"] {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+}
+
+func TestScalarWithErrors_ReportsLiteralErrorsInValidation(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        emailSchema(t),
+		RequestString: `{ echo(email: "nobody") }`,
+	})
+	expected := `Argument "email" has invalid value "nobody".` +
+		"\n" + `Expected type "Email", found "nobody"; an email address must contain "@"`
+	if len(result.Errors) != 1 || result.Errors[0].Message != expected {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestScalarWithErrors_ReportsVariableErrors(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:         emailSchema(t),
+		RequestString:  `query ($email: Email) { echo(email: $email) }`,
+		VariableValues: map[string]interface{}{"email": "nobody"},
+	})
+	expected := `Variable "$email" got invalid value "nobody"; ` +
+		`Expected type "Email", found "nobody"; an email address must contain "@"`
+	if len(result.Errors) != 1 || result.Errors[0].Message != expected {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeBadUserInput {
+		t.Fatalf("Unexpected code: %v", gqlerrors.Code(result.Errors[0]))
+	}
+}
+
+func TestScalarWithErrors_ReportsSerializationErrorsAsFieldErrors(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        emailSchema(t),
+		RequestString: `{ stored }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != `an email address must contain "@"` {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if path := result.Errors[0].Path; len(path) != 1 || path[0] != "stored" {
+		t.Fatalf("Unexpected path: %v", path)
+	}
+	if data, _ := result.Data.(map[string]interface{}); data["stored"] != nil {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+}
+
+func TestInt_ReportsOverflowsAsErrors(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"counter": &graphql.Field{
+					Type: graphql.Int,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return int64(1) << 40, nil
+					},
+				},
+				"double": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"n": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["n"].(int) * 2, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ counter }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "Int cannot represent non 32-bit signed integer value: 1099511627776" {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if data, _ := result.Data.(map[string]interface{}); data["counter"] != nil {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+
+	for _, value := range []interface{}{json.Number("2147483648"), 2147483648.0, 1.5, "21"} {
+		result = graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  `query($n: Int) { double(n: $n) }`,
+			VariableValues: map[string]interface{}{"n": value},
+		})
+		if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "Int cannot represent") {
+			t.Fatalf("Unexpected errors for %#v: %v", value, result.Errors)
+		}
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `query($n: Int) { double(n: $n) }`,
+		VariableValues: map[string]interface{}{"n": json.Number("21")},
+	})
+	expected := &graphql.Result{Data: map[string]interface{}{"double": 42}}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -57,7 +57,8 @@ func TestTypeSystem_Scalar_SerializesOutputInt(t *testing.T) {
 		// Too big to represent as an Int in Go, JavaScript or GraphQL
 		{float64(1e100), nil},
 		{float64(-1e100), nil},
-		{"-1.1", -1},
+		{"-1.1", nil},
+		{"-1", -1},
 		{"one", nil},
 		{false, 0},
 		{true, 1},
@@ -1,6 +1,7 @@
 package graphql
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 )
@@ -9,6 +10,7 @@ func TestCoerceInt(t *testing.T) {
 	tests := []struct {
 		in   interface{}
 		want interface{}
+		err  bool
 	}{
 		{
 			in:   false,
@@ -31,50 +33,50 @@ func TestCoerceInt(t *testing.T) {
 			want: nil,
 		},
 		{
-			in:   int(math.MinInt32) - 1,
-			want: nil,
+			in:  int(math.MinInt32) - 1,
+			err: true,
 		},
 		{
-			in:   int(math.MaxInt32) + 1,
-			want: nil,
+			in:  int(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
-			in:   uint(math.MaxInt32) + 1,
-			want: nil,
+			in:  uint(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
-			in:   uint32(math.MaxInt32) + 1,
-			want: nil,
+			in:  uint32(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
-			in:   int64(math.MinInt32) - 1,
-			want: nil,
+			in:  int64(math.MinInt32) - 1,
+			err: true,
 		},
 		{
-			in:   int64(math.MaxInt32) + 1,
-			want: nil,
+			in:  int64(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
-			in:   uint64(math.MaxInt32) + 1,
-			want: nil,
+			in:  uint64(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
 			// need to subtract more than one because of float32 precision
-			in:   float32(math.MinInt32) - 1000,
-			want: nil,
+			in:  float32(math.MinInt32) - 1000,
+			err: true,
 		},
 		{
 			// need to add more than one because of float32 precision
-			in:   float32(math.MaxInt32) + 1000,
-			want: nil,
+			in:  float32(math.MaxInt32) + 1000,
+			err: true,
 		},
 		{
-			in:   float64(math.MinInt32) - 1,
-			want: nil,
+			in:  float64(math.MinInt32) - 1,
+			err: true,
 		},
 		{
-			in:   float64(math.MaxInt32) + 1,
-			want: nil,
+			in:  float64(math.MaxInt32) + 1,
+			err: true,
 		},
 		{
 			in:   int(math.MinInt32),
@@ -233,17 +235,33 @@ func TestCoerceInt(t *testing.T) {
 			want: nil,
 		},
 		{
-			in:   "I'm not a number",
-			want: nil,
+			in:  "I'm not a number",
+			err: true,
 		},
 		{
-			in:   make(map[string]interface{}),
-			want: nil,
+			in:  "1.5",
+			err: true,
+		},
+		{
+			in:  json.Number("1.5"),
+			err: true,
+		},
+		{
+			in:   json.Number("42"),
+			want: int(42),
+		},
+		{
+			in:  make(map[string]interface{}),
+			err: true,
 		},
 	}
 
 	for i, tt := range tests {
-		if got, want := coerceInt(tt.in), tt.want; got != want {
+		got, err := coerceInt(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("%d: in=%v, got error %v, want error %v", i, tt.in, err, tt.err)
+		}
+		if want := tt.want; got != want {
 			t.Errorf("%d: in=%v, got=%v, want=%v", i, tt.in, got, want)
 		}
 	}
@@ -253,6 +271,7 @@ func TestCoerceFloat(t *testing.T) {
 	tests := []struct {
 		in   interface{}
 		want interface{}
+		err  bool
 	}{
 		{
 			in:   false,
@@ -431,17 +450,21 @@ func TestCoerceFloat(t *testing.T) {
 			want: nil,
 		},
 		{
-			in:   "I'm not a number",
-			want: nil,
+			in:  "I'm not a number",
+			err: true,
 		},
 		{
-			in:   make(map[string]interface{}),
-			want: nil,
+			in:  make(map[string]interface{}),
+			err: true,
 		},
 	}
 
 	for i, tt := range tests {
-		if got, want := coerceFloat(tt.in), tt.want; got != want {
+		got, err := coerceFloat(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("%d: in=%v, got error %v, want error %v", i, tt.in, err, tt.err)
+		}
+		if want := tt.want; got != want {
 			t.Errorf("%d: in=%v, got=%v, want=%v", i, tt.in, got, want)
 		}
 	}
@@ -1,5 +1,11 @@
 package graphql
 
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
 type SchemaConfig struct {
 	Query        *Object
 	Mutation     *Object
@@ -7,6 +13,44 @@ type SchemaConfig struct {
 	Types        []Type
 	Directives   []*Directive
 	Extensions   []Extension
+
+	// ErrorPresenter, if set, is used to present every error of a request
+	// executed against this schema. It can be overridden per request.
+	ErrorPresenter ErrorPresenterFn
+
+	// FieldMiddleware wraps the resolver of every field of the schema,
+	// including fields resolved by DefaultResolveFn.
+	FieldMiddleware []FieldMiddleware
+
+	// DocumentCache, if set, caches the parsed and validated documents of
+	// the requests executed by Do against this schema. It can be overridden
+	// per request.
+	DocumentCache *DocumentCache
+
+	// PersistedQueryStore, if set, enables the automatic persisted queries
+	// protocol for the requests executed by Do against this schema. It can
+	// be overridden per request.
+	PersistedQueryStore PersistedQueryStore
+
+	// TrustedDocuments, if set, restricts the requests executed by Do
+	// against this schema to those documents, which are validated by
+	// NewSchema.
+	TrustedDocuments *TrustedDocuments
+
+	// MaxBatchSize, if set, is the maximum number of operations of the
+	// batches executed by DoBatch against this schema.
+	MaxBatchSize int
+
+	// GoTypes maps Go types to the Object types of their values, in
+	// addition to the GoTypes of the ObjectConfig of each type, to resolve
+	// the runtime type of the values of abstract types.
+	GoTypes map[reflect.Type]*Object
+
+	// SQLNullArguments, if set, makes the nullable String, ID, Int, Float,
+	// Boolean and DateTime arguments be passed to resolvers as the
+	// sql.NullString, sql.NullInt64, sql.NullFloat64, sql.NullBool and
+	// sql.NullTime values, which are invalid for null and omitted arguments.
+	SQLNullArguments bool
 }
 
 type TypeMap map[string]Type
@@ -41,8 +85,23 @@ type Schema struct {
 	implementations  map[string][]*Object
 	possibleTypeMap  map[string]map[string]bool
 	extensions       []Extension
+	errorPresenter   ErrorPresenterFn
+	fieldMiddleware  []FieldMiddleware
+	documentCache    *DocumentCache
+
+	persistedQueryStore PersistedQueryStore
+	trustedDocuments    *TrustedDocuments
+	maxBatchSize        int
+	goTypes             map[reflect.Type]*Object
+	sqlNullArguments    bool
+
+	// id identifies the schema in the DocumentCache.
+	id uint64
 }
 
+// schemaCount is used to give each schema its id.
+var schemaCount uint64
+
 func NewSchema(config SchemaConfig) (Schema, error) {
 	var err error
 
@@ -137,10 +196,50 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 		}
 	}
 
+	// Register the Go types of the Object types
+	schema.goTypes = map[reflect.Type]*Object{}
+	for _, ttype := range schema.typeMap {
+		if ttype, ok := ttype.(*Object); ok {
+			for _, goType := range ttype.typeConfig.GoTypes {
+				if err := schema.registerGoType(goType, ttype); err != nil {
+					return schema, err
+				}
+			}
+		}
+	}
+	for goType, ttype := range config.GoTypes {
+		if err := schema.registerGoType(goType, ttype); err != nil {
+			return schema, err
+		}
+	}
+
+	// Enforce union types to be resolvable, which is checked once the Go
+	// types are registered
+	for _, ttype := range schema.typeMap {
+		if ttype, ok := ttype.(*Union); ok {
+			if err := schema.assertUnionIsResolvable(ttype); err != nil {
+				return schema, err
+			}
+		}
+	}
+
 	// Add extensions from config
 	if len(config.Extensions) != 0 {
 		schema.extensions = config.Extensions
 	}
+	schema.errorPresenter = config.ErrorPresenter
+	schema.fieldMiddleware = config.FieldMiddleware
+	schema.documentCache = config.DocumentCache
+	schema.persistedQueryStore = config.PersistedQueryStore
+	schema.maxBatchSize = config.MaxBatchSize
+	schema.sqlNullArguments = config.SQLNullArguments
+	if config.TrustedDocuments != nil {
+		if err := config.TrustedDocuments.validate(&schema); err != nil {
+			return schema, err
+		}
+		schema.trustedDocuments = config.TrustedDocuments
+	}
+	schema.id = atomic.AddUint64(&schemaCount, 1)
 
 	return schema, nil
 }
@@ -267,6 +366,12 @@ func (gq *Schema) AddExtensions(e ...Extension) {
 	gq.extensions = append(gq.extensions, e...)
 }
 
+// AddFieldMiddleware can be used to add middleware around the resolver of
+// every field of the schema
+func (gq *Schema) AddFieldMiddleware(m ...FieldMiddleware) {
+	gq.fieldMiddleware = append(gq.fieldMiddleware, m...)
+}
+
 // map-reduce
 func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap, error) {
 	var err error
@@ -543,3 +648,59 @@ func isTypeSubTypeOf(schema *Schema, maybeSubType Type, superType Type) bool {
 	// Otherwise, the child type is not a valid subtype of the parent type.
 	return false
 }
+
+func (gq *Schema) registerGoType(goType reflect.Type, ttype *Object) error {
+	if goType == nil || ttype == nil {
+		return nil
+	}
+	if existing, ok := gq.goTypes[goType]; ok && existing != ttype {
+		return fmt.Errorf(`Go type %v is registered for both "%v" and "%v".`, goType, existing, ttype)
+	}
+	if gq.Type(ttype.Name()) != ttype {
+		return fmt.Errorf(`Go type %v is registered for "%v", which is not a type of the schema.`, goType, ttype)
+	}
+	gq.goTypes[goType] = ttype
+	return nil
+}
+
+// GoTypeObject returns the Object type registered for the Go type of the
+// value, or of the value it points to.
+func (gq *Schema) GoTypeObject(value interface{}) *Object {
+	if len(gq.goTypes) == 0 || value == nil {
+		return nil
+	}
+	goType := reflect.TypeOf(value)
+	if ttype, ok := gq.goTypes[goType]; ok {
+		return ttype
+	}
+	if goType.Kind() == reflect.Ptr {
+		return gq.goTypes[goType.Elem()]
+	}
+	return gq.goTypes[reflect.PtrTo(goType)]
+}
+
+// assertUnionIsResolvable checks that the runtime type of the values of the
+// union can be resolved, by its ResolveType or ResolveTypeName functions, or
+// else by the IsTypeOf functions or the Go types registered for each of its
+// possible types.
+func (gq *Schema) assertUnionIsResolvable(union *Union) error {
+	if union.ResolveType != nil || union.ResolveTypeName != nil {
+		return nil
+	}
+	registered := map[*Object]bool{}
+	for _, ttype := range gq.goTypes {
+		registered[ttype] = true
+	}
+	for _, ttype := range union.Types() {
+		if err := invariantf(
+			ttype.IsTypeOf != nil || registered[ttype],
+			`Union Type %v does not provide a "resolveType" function `+
+				`and possible Type %v does not provide a "isTypeOf" `+
+				`function. There is no way to resolve this possible type `+
+				`during execution.`, union, ttype,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
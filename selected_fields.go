@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// SelectedField is a field selected on the value of a field, as collected by
+// the executor: fragments are expanded, the @skip and @include directives are
+// applied, and the fields of a same response name are merged.
+type SelectedField struct {
+	// Name is the name of the field in the schema.
+	Name string
+	// ResponseName is the name of the field in the result, i.e. its alias
+	// if it has one.
+	ResponseName string
+	// Definition is the definition of the field.
+	Definition *FieldDefinition
+	// Args are the coerced arguments of the field.
+	Args map[string]interface{}
+	// FieldASTs are the AST nodes of the field.
+	FieldASTs []*ast.Field
+	// Fields are the sub-fields selected on the field, if its type is an
+	// Object type. Use FieldsFor for Interface and Union types.
+	Fields []*SelectedField
+
+	eCtx *executionContext
+}
+
+// FieldsFor returns the sub-fields selected on the field, for the given
+// runtime type of its value.
+func (f *SelectedField) FieldsFor(runtimeType *Object) []*SelectedField {
+	return selectedFields(f.eCtx, runtimeType, f.FieldASTs)
+}
+
+// SelectedFields returns the fields selected on the value of the resolved
+// field, if its type is an Object type. Use SelectedFieldsFor for Interface
+// and Union types.
+func (info ResolveInfo) SelectedFields() []*SelectedField {
+	runtimeType, ok := GetNamed(info.ReturnType).(*Object)
+	if !ok {
+		return nil
+	}
+	return info.SelectedFieldsFor(runtimeType)
+}
+
+// SelectedFieldsFor returns the fields selected on the value of the resolved
+// field, for the given runtime type of that value.
+func (info ResolveInfo) SelectedFieldsFor(runtimeType *Object) []*SelectedField {
+	eCtx := &executionContext{
+		Schema:         info.Schema,
+		Fragments:      info.Fragments,
+		Root:           info.RootValue,
+		Operation:      info.Operation,
+		VariableValues: info.VariableValues,
+	}
+	return selectedFields(eCtx, runtimeType, info.FieldASTs)
+}
+
+// selectedFields collects the sub-fields of the field ASTs for the runtime
+// type, along with their own sub-fields when their type is an Object type.
+func selectedFields(eCtx *executionContext, runtimeType *Object, fieldASTs []*ast.Field) []*SelectedField {
+	if runtimeType == nil {
+		return nil
+	}
+	collected := newCollectedFields()
+	visitedFragmentNames := map[string]bool{}
+	for _, fieldAST := range fieldASTs {
+		if fieldAST == nil || fieldAST.SelectionSet == nil {
+			continue
+		}
+		collectFields(collectFieldsParams{
+			ExeContext:           eCtx,
+			RuntimeType:          runtimeType,
+			SelectionSet:         fieldAST.SelectionSet,
+			Fields:               collected,
+			VisitedFragmentNames: visitedFragmentNames,
+		})
+	}
+
+	fields := make([]*SelectedField, 0, len(collected.responseNames))
+	for _, responseName := range collected.responseNames {
+		subFieldASTs := collected.fields[responseName]
+		fieldName := fieldASTName(subFieldASTs[0])
+		fieldDef := getFieldDef(eCtx.Schema, runtimeType, fieldName)
+		if fieldDef == nil {
+			continue
+		}
+		field := &SelectedField{
+			Name:         fieldName,
+			ResponseName: responseName,
+			Definition:   fieldDef,
+			Args:         getFieldArgumentValues(eCtx.Schema, fieldDef, subFieldASTs[0].Arguments, eCtx.VariableValues),
+			FieldASTs:    subFieldASTs,
+			eCtx:         eCtx,
+		}
+		if objectType, ok := GetNamed(fieldDef.Type).(*Object); ok {
+			field.Fields = selectedFields(eCtx, objectType, subFieldASTs)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
@@ -0,0 +1,182 @@
+package graphql_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// describeSelectedFields describes the selected fields as
+// `responseName:name(args){subFields}`, to compare them in tests.
+func describeSelectedFields(fields []*graphql.SelectedField) string {
+	var descriptions []string
+	for _, field := range fields {
+		description := field.ResponseName + ":" + field.Definition.Name
+		if len(field.Args) > 0 {
+			description += fmt.Sprintf("(%v)", field.Args)
+		}
+		if field.Fields != nil {
+			description += "{" + describeSelectedFields(field.Fields) + "}"
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, " ")
+}
+
+func selectedFieldsSchema(t *testing.T, resolve graphql.FieldResolveFn) (graphql.Schema, *graphql.Object) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.ID},
+		},
+	})
+	postType := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "Post",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.ID},
+			"title": &graphql.Field{Type: graphql.String},
+		},
+	})
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "Author",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.ID},
+			"name": &graphql.Field{Type: graphql.String},
+			"posts": &graphql.Field{
+				Type: graphql.NewList(postType),
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+				},
+			},
+		},
+	})
+	nodeInterface.ResolveType = func(p graphql.ResolveTypeParams) *graphql.Object {
+		return postType
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"author": &graphql.Field{Type: authorType, Resolve: resolve},
+				"node":   &graphql.Field{Type: nodeInterface, Resolve: resolve},
+			},
+		}),
+		Types: []graphql.Type{postType},
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema, postType
+}
+
+func TestResolveInfo_SelectedFields(t *testing.T) {
+	var selected string
+	schema, _ := selectedFieldsSchema(t, func(p graphql.ResolveParams) (interface{}, error) {
+		selected = describeSelectedFields(p.Info.SelectedFields())
+		return nil, nil
+	})
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `
+			query ($withName: Boolean!, $limit: Int) {
+				author {
+					id
+					name @include(if: $withName)
+					... on Author {
+						recent: posts(limit: $limit) { title }
+					}
+					...AuthorPosts
+				}
+			}
+			fragment AuthorPosts on Author {
+				recent: posts(limit: $limit) { id }
+				__typename
+			}
+		`,
+		VariableValues: map[string]interface{}{"withName": false, "limit": 3},
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := "id:id recent:posts(map[limit:3]){title:title id:id} __typename:__typename"
+	if selected != expected {
+		t.Fatalf("Unexpected selected fields: %v, expected: %v", selected, expected)
+	}
+}
+
+func TestResolveInfo_SelectedFieldsForAbstractTypes(t *testing.T) {
+	var info graphql.ResolveInfo
+	schema, postType := selectedFieldsSchema(t, func(p graphql.ResolveParams) (interface{}, error) {
+		info = p.Info
+		return nil, nil
+	})
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			node {
+				id
+				... on Post { title }
+				... on Author { name }
+			}
+		}`,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if fields := info.SelectedFields(); fields != nil {
+		t.Fatalf("Expected no fields for an abstract type, got %v", describeSelectedFields(fields))
+	}
+	if selected := describeSelectedFields(info.SelectedFieldsFor(postType)); selected != "id:id title:title" {
+		t.Fatalf("Unexpected selected fields: %v", selected)
+	}
+}
+
+func TestSelectedField_FieldsFor(t *testing.T) {
+	droidType := testutil.StarWarsSchema.Type("Droid").(*graphql.Object)
+	humanType := testutil.StarWarsSchema.Type("Human").(*graphql.Object)
+	var info graphql.ResolveInfo
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: testutil.StarWarsSchema.QueryType().Fields()["hero"].Type,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						info = p.Info
+						return nil, nil
+					},
+				},
+			},
+		}),
+		Types: []graphql.Type{droidType, humanType},
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hero { ... on Droid { friends { name ... on Droid { primaryFunction } } } } }`,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	friends := info.SelectedFieldsFor(droidType)
+	if len(friends) != 1 || friends[0].Fields != nil {
+		t.Fatalf("Unexpected fields: %v", describeSelectedFields(friends))
+	}
+	expected := map[*graphql.Object]string{
+		droidType: "name:name primaryFunction:primaryFunction",
+		humanType: "name:name",
+	}
+	for runtimeType, description := range expected {
+		if selected := describeSelectedFields(friends[0].FieldsFor(runtimeType)); selected != description {
+			t.Fatalf("Unexpected selected fields for %v: %v", runtimeType, selected)
+		}
+	}
+}
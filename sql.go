@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"time"
+)
+
+// driverValue returns the value of a driver.Valuer, such as the database/sql
+// Null types, and whether value is one. The value of invalid Null types, of
+// nil pointers and of failing Value methods is nil, and []byte values are
+// converted to strings.
+func driverValue(value interface{}) (interface{}, bool) {
+	valuer, ok := value.(driver.Valuer)
+	if !ok {
+		return nil, false
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, true
+	}
+	v, err := valuer.Value()
+	if err != nil {
+		return nil, true
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b), true
+	}
+	return v, true
+}
+
+// sqlNullArguments replaces the values of the nullable String, ID, Int,
+// Float, Boolean and DateTime arguments, list items and input object fields
+// by the matching database/sql Null types, which are invalid for null and
+// omitted values. The values of non-null and other types are kept.
+func sqlNullArguments(argDefs []*Argument, args map[string]interface{}) map[string]interface{} {
+	for _, argDef := range argDefs {
+		if value := sqlNullValue(argDef.Type, args[argDef.PrivateName]); value != nil {
+			args[argDef.PrivateName] = value
+		}
+	}
+	return args
+}
+
+func sqlNullValue(ttype Input, value interface{}) interface{} {
+	switch ttype := ttype.(type) {
+	case *NonNull:
+		return sqlNullContents(ttype.OfType, value)
+	case *Scalar:
+		return sqlNull(ttype, value)
+	}
+	if isNullish(value) {
+		return nil
+	}
+	return sqlNullContents(ttype, value)
+}
+
+// sqlNullContents replaces the values of the items of lists and of the
+// fields of input objects.
+func sqlNullContents(ttype Input, value interface{}) interface{} {
+	switch ttype := ttype.(type) {
+	case *List:
+		items, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			result[i] = sqlNullValue(ttype.OfType, item)
+		}
+		return result
+	case *InputObject:
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		result := make(map[string]interface{}, len(fields))
+		for name, field := range ttype.Fields() {
+			// the null fields are kept, so that they are told from the
+			// omitted ones
+			fieldValue, ok := fields[name]
+			if fieldValue = sqlNullValue(field.Type, fieldValue); fieldValue != nil || ok {
+				result[name] = fieldValue
+			}
+		}
+		return result
+	}
+	return value
+}
+
+func sqlNull(scalar *Scalar, value interface{}) interface{} {
+	switch scalar {
+	case String, ID:
+		s, ok := value.(string)
+		return sql.NullString{String: s, Valid: ok}
+	case Int:
+		i, ok := value.(int)
+		return sql.NullInt64{Int64: int64(i), Valid: ok}
+	case Float:
+		if f, ok := value.(float32); ok {
+			value = float64(f)
+		}
+		f, ok := value.(float64)
+		return sql.NullFloat64{Float64: f, Valid: ok}
+	case Boolean:
+		b, ok := value.(bool)
+		return sql.NullBool{Bool: b, Valid: ok}
+	case DateTime:
+		t, ok := value.(time.Time)
+		return sql.NullTime{Time: t, Valid: ok}
+	}
+	return value
+}
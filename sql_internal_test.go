@@ -0,0 +1,24 @@
+package graphql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestSQLNullContents_KeepsTheNullFieldsOfInputObjects(t *testing.T) {
+	filterType := NewInputObject(InputObjectConfig{
+		Name: "Filter",
+		Fields: InputObjectConfigFieldMap{
+			"name": &InputObjectFieldConfig{Type: String},
+			"tags": &InputObjectFieldConfig{Type: NewList(String)},
+			"ids":  &InputObjectFieldConfig{Type: NewList(ID)},
+		},
+	})
+	value := map[string]interface{}{"tags": nil}
+	expected := map[string]interface{}{"name": sql.NullString{}, "tags": nil}
+	result := sqlNullContents(filterType, value)
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Expected %v, got: %v", expected, result)
+	}
+}
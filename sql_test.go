@@ -0,0 +1,187 @@
+package graphql_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type rawValuer []byte
+
+func (v rawValuer) Value() (driver.Value, error) {
+	return []byte(v), nil
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("failing valuer")
+}
+
+func TestSQLNullTypes_AreSerialized(t *testing.T) {
+	date := time.Date(2020, 2, 29, 13, 4, 5, 0, time.UTC)
+	values := map[string]interface{}{
+		"string":        sql.NullString{String: "a", Valid: true},
+		"nullString":    sql.NullString{String: "a"},
+		"nilString":     (*sql.NullString)(nil),
+		"stringPtr":     &sql.NullString{String: "b", Valid: true},
+		"int":           sql.NullInt64{Int64: 42, Valid: true},
+		"nullInt":       sql.NullInt64{},
+		"int32":         sql.NullInt32{Int32: 7, Valid: true},
+		"float":         sql.NullFloat64{Float64: 1.5, Valid: true},
+		"nullFloat":     sql.NullFloat64{},
+		"boolean":       sql.NullBool{Bool: false, Valid: true},
+		"nullBoolean":   sql.NullBool{Bool: true},
+		"dateTime":      sql.NullTime{Time: date, Valid: true},
+		"nullDateTime":  sql.NullTime{},
+		"valuer":        rawValuer("raw"),
+		"failingValuer": failingValuer{},
+	}
+	fieldTypes := map[string]graphql.Output{
+		"string":        graphql.String,
+		"nullString":    graphql.String,
+		"nilString":     graphql.String,
+		"stringPtr":     graphql.String,
+		"int":           graphql.Int,
+		"nullInt":       graphql.Int,
+		"int32":         graphql.Int,
+		"float":         graphql.Float,
+		"nullFloat":     graphql.Float,
+		"boolean":       graphql.Boolean,
+		"nullBoolean":   graphql.Boolean,
+		"dateTime":      graphql.DateTime,
+		"nullDateTime":  graphql.DateTime,
+		"valuer":        graphql.String,
+		"failingValuer": graphql.String,
+	}
+	fields := graphql.Fields{}
+	for name, ttype := range fieldTypes {
+		fields[name] = &graphql.Field{Type: ttype}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: fields,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			string nullString nilString stringPtr
+			int nullInt int32
+			float nullFloat
+			boolean nullBoolean
+			dateTime nullDateTime
+			valuer failingValuer
+		}`,
+		RootObject: values,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"string":        "a",
+			"nullString":    nil,
+			"nilString":     nil,
+			"stringPtr":     "b",
+			"int":           42,
+			"nullInt":       nil,
+			"int32":         7,
+			"float":         1.5,
+			"nullFloat":     nil,
+			"boolean":       false,
+			"nullBoolean":   nil,
+			"dateTime":      "2020-02-29T13:04:05Z",
+			"nullDateTime":  nil,
+			"valuer":        "raw",
+			"failingValuer": nil,
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestSQLNullArguments(t *testing.T) {
+	var args map[string]interface{}
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"active": &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		},
+	})
+	newSchema := func(sqlNullArguments bool) graphql.Schema {
+		schema, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					"people": &graphql.Field{
+						Type: graphql.String,
+						Args: graphql.FieldConfigArgument{
+							"id":     &graphql.ArgumentConfig{Type: graphql.ID},
+							"name":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+							"age":    &graphql.ArgumentConfig{Type: graphql.Int},
+							"score":  &graphql.ArgumentConfig{Type: graphql.Float},
+							"since":  &graphql.ArgumentConfig{Type: graphql.DateTime},
+							"tags":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.String)},
+							"filter": &graphql.ArgumentConfig{Type: filterType},
+						},
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							args = p.Args
+							return nil, nil
+						},
+					},
+				},
+			}),
+			SQLNullArguments: sqlNullArguments,
+		})
+		if err != nil {
+			t.Fatalf("Error in schema %v", err.Error())
+		}
+		return schema
+	}
+	query := `query($score: Float, $tags: [String]) {
+		people(name: "Alice", age: 42, score: $score, tags: $tags, filter: {active: true})
+	}`
+
+	result := graphql.Do(graphql.Params{
+		Schema:         newSchema(true),
+		RequestString:  query,
+		VariableValues: map[string]interface{}{"score": 1.5, "tags": []interface{}{"a", nil}},
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{
+		"id":    sql.NullString{},
+		"name":  "Alice",
+		"age":   sql.NullInt64{Int64: 42, Valid: true},
+		"score": sql.NullFloat64{Float64: 1.5, Valid: true},
+		"since": sql.NullTime{},
+		"tags":  []interface{}{sql.NullString{String: "a", Valid: true}, sql.NullString{}},
+		"filter": map[string]interface{}{
+			"name":   sql.NullString{},
+			"active": sql.NullBool{Bool: true, Valid: true},
+		},
+	}
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf("Unexpected arguments, Diff: %v", testutil.Diff(expected, args))
+	}
+
+	graphql.Do(graphql.Params{
+		Schema:         newSchema(false),
+		RequestString:  query,
+		VariableValues: map[string]interface{}{"score": 1.5, "tags": []interface{}{"a", nil}},
+	})
+	if _, ok := args["age"].(int); !ok {
+		t.Fatalf("Expected the arguments to keep their types by default, got %#v", args)
+	}
+}
@@ -0,0 +1,638 @@
+// Package sse implements the graphql-sse protocol, serving the operations
+// of a schema, and especially its subscriptions, as Server-Sent Events:
+//
+//	https://github.com/enisdenjo/graphql-sse/blob/master/PROTOCOL.md
+//
+// Unlike WebSocket connections, the event streams are plain HTTP responses,
+// and so go through the proxies which do not support WebSockets.
+//
+// In the distinct connections mode, the results of each operation, of a GET
+// or POST request accepting text/event-stream, are streamed as next events
+// of its response until a complete event.
+//
+// In the single connection mode, a stream is reserved by a PUT request,
+// whose response is its token, and opened by a GET request of the token,
+// set in the X-GraphQL-Event-Stream-Token header or the token query
+// parameter. The operations are then sent by POST requests of the token,
+// with an operationId extension, and their results are streamed as the
+// next and complete events of the stream, whose data are objects of the id
+// of the operation and of its result as payload. DELETE requests of the
+// token and of the operationId query parameter cancel the operations.
+package sse
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// ContentTypeEventStream is the media type of the event streams, which
+	// the requests of the streams must accept.
+	ContentTypeEventStream = "text/event-stream"
+
+	// TokenHeader is the header of the token of the streams of the single
+	// connection mode.
+	TokenHeader = "X-GraphQL-Event-Stream-Token"
+
+	// DefaultHeartbeatInterval is the default interval of the heartbeat
+	// comments of the streams.
+	DefaultHeartbeatInterval = 12 * time.Second
+
+	// DefaultMaxBodySize is the default maximum size in bytes of the bodies
+	// of the POST requests.
+	DefaultMaxBodySize = 1 << 20
+)
+
+// reservationTimeout is the time within which the reserved streams of the
+// single connection mode must be opened.
+const reservationTimeout = 30 * time.Second
+
+// ContextFn returns the context of the execution of a request.
+type ContextFn func(r *http.Request) context.Context
+
+// RootObjectFn returns the root value of the execution of a request.
+type RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
+
+// ResumeFn is called for the requests of the distinct connections mode with
+// a Last-Event-ID header, i.e. of clients reconnecting after the event of
+// the id, and can change the params of their operation to resume it, e.g.
+// by setting a variable of the last event or a value of the context.
+type ResumeFn func(r *http.Request, lastEventID string, params *graphql.Params)
+
+// EventIDFn returns the id of the next event of a result, e.g. the id of the
+// item of its data, sent back by the clients reconnecting after it as their
+// Last-Event-ID header. The events have no id if empty.
+type EventIDFn func(ctx context.Context, result *graphql.Result) string
+
+// Config are the options of a Handler.
+type Config struct {
+	// Schema is the schema served by the handler.
+	Schema *graphql.Schema
+
+	// ContextFn returns the context of the execution of a request, the
+	// context of the request if nil. The operations of the single
+	// connection mode are executed in the context of the request of their
+	// stream.
+	ContextFn ContextFn
+
+	// RootObjectFn, if set, returns the root value of the execution of each
+	// operation.
+	RootObjectFn RootObjectFn
+
+	// ResumeFn, if set, is called for the requests with a Last-Event-ID
+	// header.
+	ResumeFn ResumeFn
+
+	// EventIDFn, if set, returns the ids of the next events.
+	EventIDFn EventIDFn
+
+	// HeartbeatInterval is the interval of the comments sent to keep the
+	// streams alive, DefaultHeartbeatInterval if zero. Negative values
+	// disable the heartbeats.
+	HeartbeatInterval time.Duration
+
+	// MaxBodySize is the maximum size in bytes of the bodies of the POST
+	// requests, DefaultMaxBodySize if zero.
+	MaxBodySize int64
+
+	// UseNumber makes the numbers of the variables be decoded as
+	// json.Number values, see graphql.RequestDecoder.
+	UseNumber bool
+}
+
+// Handler serves a schema as Server-Sent Events.
+type Handler struct {
+	config Config
+
+	mu      sync.Mutex
+	streams map[string]*stream
+}
+
+// New returns a handler of the config, whose Schema must be set.
+func New(config *Config) *Handler {
+	if config == nil || config.Schema == nil {
+		panic("sse: undefined Schema")
+	}
+	h := &Handler{config: *config, streams: map[string]*stream{}}
+	if h.config.HeartbeatInterval == 0 {
+		h.config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if h.config.MaxBodySize == 0 {
+		h.config.MaxBodySize = DefaultMaxBodySize
+	}
+	return h
+}
+
+// ServeHTTP serves the requests of both modes, the ones of the single
+// connection mode being the PUT requests and the requests with a token.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.config.ContextFn != nil {
+		ctx = h.config.ContextFn(r)
+	}
+	token := r.Header.Get(TokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	switch {
+	case r.Method == http.MethodPut:
+		h.reserve(w)
+	case token != "":
+		h.serveSingle(ctx, w, r, token)
+	default:
+		h.serveDistinct(ctx, w, r)
+	}
+}
+
+// stream is an event stream.
+type stream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+
+	// the state of the streams of the single connection mode, whose ctx is
+	// set once they are open, and which are closing once their operations
+	// are being waited for
+	ctx        context.Context
+	closing    bool
+	operations map[string]context.CancelFunc
+	wait       sync.WaitGroup
+}
+
+var errStreamClosed = errors.New("sse: stream closed")
+
+// open writes the header of the stream.
+func (s *stream) open(w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("the ResponseWriter is not a http.Flusher")
+	}
+	s.w, s.flusher = w, flusher
+	header := w.Header()
+	header.Set("Content-Type", ContentTypeEventStream+"; charset=utf-8")
+	header.Set("Cache-Control", "no-cache")
+	// disables the buffering of the responses by nginx
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return nil
+}
+
+// event writes an event of the data, which must not contain line breaks.
+func (s *stream) event(event, id string, data []byte) error {
+	var buff bytes.Buffer
+	buff.WriteString("event: " + event + "\n")
+	if id != "" && !strings.ContainsAny(id, "\r\n") {
+		buff.WriteString("id: " + id + "\n")
+	}
+	buff.WriteString("data: ")
+	buff.Write(data)
+	buff.WriteString("\n\n")
+	return s.write(buff.Bytes())
+}
+
+// heartbeat writes an empty comment, so that the idle streams are not
+// closed by proxies.
+func (s *stream) heartbeat() error {
+	return s.write([]byte(":\n\n"))
+}
+
+func (s *stream) write(p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errStreamClosed
+	}
+	if _, err := s.w.Write(p); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// close makes the next writes fail, once the ResponseWriter can not be used.
+func (s *stream) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// serveDistinct executes the operation of a request of the distinct
+// connections mode, streaming its results.
+func (h *Handler) serveDistinct(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var (
+		request graphql.Request
+		status  int
+		err     error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		request, status, err = h.getRequest(r)
+	case http.MethodPost:
+		request, status, err = h.postRequest(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT")
+		status, err = http.StatusMethodNotAllowed, fmt.Errorf("the %v method is not supported", r.Method)
+	}
+	if err == nil && !acceptsEventStream(r) {
+		status, err = http.StatusNotAcceptable, fmt.Errorf("the Accept header must accept %v", ContentTypeEventStream)
+	}
+	if err != nil {
+		fail(w, status, "%v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	params := h.params(ctx, r, request)
+	if r.Method == http.MethodGet {
+		// the mutations are rejected once the persisted query or trusted
+		// document of the request is resolved
+		params.AllowedOperationTypes = []string{ast.OperationTypeQuery, ast.OperationTypeSubscription}
+	}
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" && h.config.ResumeFn != nil {
+		h.config.ResumeFn(r, lastEventID, &params)
+	}
+	results := execute(params)
+	// the results of the canceled operations are drained, so that their
+	// execution ends
+	defer func() {
+		go func() {
+			for range results {
+			}
+		}()
+	}()
+
+	first, ok := <-results
+	if ok && first.IsRequestError() {
+		status := http.StatusBadRequest
+		if gqlerrors.Code(first.Errors[0]) == gqlerrors.ErrCodeOperationNotAllowed {
+			status = http.StatusMethodNotAllowed
+		}
+		writeJSON(w, status, first)
+		return
+	}
+	s := &stream{}
+	if err := s.open(w); err != nil {
+		fail(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	defer s.close()
+	if ok && h.next(ctx, s, "", first) != nil {
+		return
+	}
+
+	var heartbeats <-chan time.Time
+	if h.config.HeartbeatInterval > 0 {
+		ticker := time.NewTicker(h.config.HeartbeatInterval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
+	}
+	for ok {
+		var result *graphql.Result
+		select {
+		case result, ok = <-results:
+			if ok && h.next(ctx, s, "", result) != nil {
+				return
+			}
+		case <-heartbeats:
+			if s.heartbeat() != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+	s.event("complete", "", nil)
+}
+
+// reserve reserves a stream of the single connection mode, writing its
+// token.
+func (h *Handler) reserve(w http.ResponseWriter) {
+	var random [16]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		fail(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	token := hex.EncodeToString(random[:])
+	s := &stream{operations: map[string]context.CancelFunc{}}
+	h.mu.Lock()
+	h.streams[token] = s
+	h.mu.Unlock()
+	time.AfterFunc(reservationTimeout, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.ctx == nil && h.streams[token] == s {
+			delete(h.streams, token)
+		}
+	})
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(token))
+}
+
+// serveSingle serves a request of the stream of the token of the single
+// connection mode.
+func (h *Handler) serveSingle(ctx context.Context, w http.ResponseWriter, r *http.Request, token string) {
+	h.mu.Lock()
+	s, ok := h.streams[token]
+	h.mu.Unlock()
+	if !ok {
+		fail(w, http.StatusNotFound, "stream not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		h.openSingle(ctx, w, r, token, s)
+	case http.MethodPost:
+		h.subscribe(w, r, s)
+	case http.MethodDelete:
+		id := r.URL.Query().Get("operationId")
+		s.mu.Lock()
+		cancel, ok := s.operations[id]
+		delete(s.operations, id)
+		s.mu.Unlock()
+		if ok {
+			cancel()
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+		fail(w, http.StatusMethodNotAllowed, "the %v method is not supported", r.Method)
+	}
+}
+
+// openSingle opens a stream of the single connection mode, which is served
+// until the client disconnects, canceling its operations.
+func (h *Handler) openSingle(ctx context.Context, w http.ResponseWriter, r *http.Request, token string, s *stream) {
+	if !acceptsEventStream(r) {
+		fail(w, http.StatusNotAcceptable, "the Accept header must accept %v", ContentTypeEventStream)
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		fail(w, http.StatusConflict, "stream already open")
+		return
+	}
+	s.ctx = ctx
+	err := s.open(w)
+	s.mu.Unlock()
+	if err != nil {
+		fail(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	defer func() {
+		h.mu.Lock()
+		delete(h.streams, token)
+		h.mu.Unlock()
+		// no operation can be started once its operations are waited for
+		s.mu.Lock()
+		s.closing = true
+		s.mu.Unlock()
+		cancel()
+		s.wait.Wait()
+		s.close()
+	}()
+
+	var heartbeats <-chan time.Time
+	if h.config.HeartbeatInterval > 0 {
+		ticker := time.NewTicker(h.config.HeartbeatInterval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
+	}
+	for {
+		select {
+		case <-heartbeats:
+			if s.heartbeat() != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// subscribe starts the operation of a POST request of the single connection
+// mode, whose results are streamed as the events of the stream.
+func (h *Handler) subscribe(w http.ResponseWriter, r *http.Request, s *stream) {
+	request, status, err := h.postRequest(w, r)
+	if err != nil {
+		fail(w, status, "%v", err)
+		return
+	}
+	id, _ := request.Extensions["operationId"].(string)
+	if id == "" {
+		fail(w, http.StatusBadRequest, "missing operationId extension")
+		return
+	}
+	s.mu.Lock()
+	if s.ctx == nil || s.closing {
+		s.mu.Unlock()
+		fail(w, http.StatusConflict, "stream not open")
+		return
+	}
+	if _, ok := s.operations[id]; ok {
+		s.mu.Unlock()
+		fail(w, http.StatusConflict, "operation %v already exists", id)
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.operations[id] = cancel
+	s.wait.Add(1)
+	s.mu.Unlock()
+
+	params := h.params(ctx, r, request)
+	go func() {
+		defer s.wait.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.operations, id)
+			s.mu.Unlock()
+			cancel()
+		}()
+		results := execute(params)
+		for result := range results {
+			if ctx.Err() == nil {
+				h.next(ctx, s, id, result)
+			}
+		}
+		if ctx.Err() == nil {
+			data, _ := json.Marshal(map[string]interface{}{"id": id})
+			s.event("complete", "", data)
+		}
+	}()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// next writes the next event of a result, of the operation of the id in the
+// single connection mode.
+func (h *Handler) next(ctx context.Context, s *stream, id string, result *graphql.Result) error {
+	var eventID string
+	if h.config.EventIDFn != nil {
+		eventID = h.config.EventIDFn(ctx, result)
+	}
+	var payload interface{} = result
+	if id != "" {
+		payload = map[string]interface{}{"id": id, "payload": result}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		data, _ = json.Marshal(&graphql.Result{
+			Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())},
+		})
+	}
+	return s.event("next", eventID, data)
+}
+
+func (h *Handler) params(ctx context.Context, r *http.Request, request graphql.Request) graphql.Params {
+	params := request.Params(ctx, *h.config.Schema)
+	if h.config.RootObjectFn != nil {
+		params.RootObject = h.config.RootObjectFn(ctx, r)
+	}
+	return params
+}
+
+// execute executes the operation of the params, returning the channel of
+// its results, which is closed once it completes. The operation of the
+// persisted queries and trusted documents is the one of their resolved
+// document.
+func execute(p graphql.Params) chan *graphql.Result {
+	params, result := graphql.ParseAndValidate(p)
+	if result == nil {
+		if operation := params.Operation(); operation != nil && operation.Operation == ast.OperationTypeSubscription {
+			return graphql.ExecuteSubscription(params)
+		}
+		result = graphql.Execute(params)
+	}
+	results := make(chan *graphql.Result, 1)
+	results <- result
+	close(results)
+	return results
+}
+
+// getRequest reads the request of the query parameters of a GET request.
+func (h *Handler) getRequest(r *http.Request) (graphql.Request, int, error) {
+	values := r.URL.Query()
+	request := graphql.Request{
+		Query:         values.Get("query"),
+		OperationName: values.Get("operationName"),
+		DocumentID:    values.Get("documentId"),
+	}
+	for name, value := range map[string]*map[string]interface{}{
+		"variables":  &request.Variables,
+		"extensions": &request.Extensions,
+	} {
+		if values.Get(name) == "" {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(values.Get(name)))
+		if h.config.UseNumber {
+			decoder.UseNumber()
+		}
+		if err := decoder.Decode(value); err != nil {
+			return request, http.StatusBadRequest, fmt.Errorf("invalid %v parameter: %v", name, err)
+		}
+	}
+	if request.Query == "" && request.DocumentID == "" && request.Extensions == nil {
+		return request, http.StatusBadRequest, errors.New("missing query parameter")
+	}
+	return request, http.StatusOK, nil
+}
+
+// postRequest reads the request of the JSON body of a POST request.
+func (h *Handler) postRequest(w http.ResponseWriter, r *http.Request) (graphql.Request, int, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return graphql.Request{}, http.StatusUnsupportedMediaType, errors.New("the Content-Type header must be application/json")
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return graphql.Request{}, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported charset %v", charset)
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.config.MaxBodySize))
+	if err != nil {
+		return graphql.Request{}, http.StatusRequestEntityTooLarge, fmt.Errorf("the body is larger than %v bytes", h.config.MaxBodySize)
+	}
+	decoder := graphql.NewRequestDecoder(bytes.NewReader(body))
+	if h.config.UseNumber {
+		decoder.UseNumber()
+	}
+	requests, batch, err := decoder.Decode()
+	if err != nil {
+		return graphql.Request{}, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err)
+	}
+	if batch {
+		return graphql.Request{}, http.StatusBadRequest, errors.New("batches are not supported")
+	}
+	request := requests[0]
+	if request.Query == "" && request.DocumentID == "" && request.Extensions == nil {
+		return request, http.StatusBadRequest, errors.New("missing query")
+	}
+	return request, http.StatusOK, nil
+}
+
+// fail writes the error of a request which can not be served.
+func fail(w http.ResponseWriter, status int, format string, a ...interface{}) {
+	err := gqlerrors.NewFormattedError(fmt.Sprintf(format, a...))
+	err.Extensions = map[string]interface{}{"code": gqlerrors.ErrCodeBadRequest}
+	writeJSON(w, status, &graphql.Result{Errors: []gqlerrors.FormattedError{err}})
+}
+
+// writeJSON writes the result of a request which was not executed, whose
+// data entry is absent rather than null.
+func writeJSON(w http.ResponseWriter, status int, result *graphql.Result) {
+	buff, err := json.Marshal(struct {
+		Errors     []gqlerrors.FormattedError `json:"errors"`
+		Extensions map[string]interface{}     `json:"extensions,omitempty"`
+	}{result.Errors, result.Extensions})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(buff)))
+	w.WriteHeader(status)
+	w.Write(buff)
+}
+
+// acceptsEventStream reports whether the Accept header of the request
+// accepts ContentTypeEventStream.
+func acceptsEventStream(r *http.Request) bool {
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		switch mediaType {
+		case ContentTypeEventStream, "text/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
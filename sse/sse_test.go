@@ -0,0 +1,335 @@
+package sse_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/sse"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func testSchema(t *testing.T, stopped chan<- struct{}) *graphql.Schema {
+	schema, err := graphql.NewSchema(testSchemaConfig(stopped))
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return &schema
+}
+
+func testSchemaConfig(stopped chan<- struct{}) graphql.SchemaConfig {
+	return graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "Hello", nil
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"ok": &graphql.Field{Type: graphql.Boolean},
+			},
+		}),
+		Subscription: testutil.NewCountdownSubscription(stopped),
+	}
+}
+
+type event struct {
+	name, id, data string
+	comment        bool
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) event {
+	var e event
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return e
+		case strings.HasPrefix(line, ":"):
+			e.comment = true
+		case strings.HasPrefix(line, "event: "):
+			e.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			e.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func waitStopped(t *testing.T, stopped <-chan struct{}) {
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the subscription to be canceled")
+	}
+}
+
+func TestHandler_StreamsTheResultsOfDistinctConnections(t *testing.T) {
+	h := sse.New(&sse.Config{
+		Schema:            testSchema(t, nil),
+		HeartbeatInterval: -1,
+		EventIDFn: func(ctx context.Context, result *graphql.Result) string {
+			if data, ok := result.Data.(map[string]interface{}); ok {
+				if counter, ok := data["counter"].(int); ok {
+					return strconv.Itoa(counter)
+				}
+			}
+			return ""
+		},
+		ResumeFn: func(r *http.Request, lastEventID string, params *graphql.Params) {
+			from, _ := strconv.Atoi(lastEventID)
+			params.VariableValues["from"] = from
+		},
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	tests := []struct {
+		lastEventID string
+		expected    []event
+	}{
+		{
+			expected: []event{
+				{name: "next", id: "1", data: `{"data":{"counter":1}}`},
+				{name: "next", id: "2", data: `{"data":{"counter":2}}`},
+				{name: "complete"},
+			},
+		},
+		{
+			lastEventID: "1",
+			expected: []event{
+				{name: "next", id: "2", data: `{"data":{"counter":2}}`},
+				{name: "complete"},
+			},
+		},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest("POST", server.URL, strings.NewReader(`{"query": "subscription($from: Int) { counter(from: $from, to: 2) }", "variables": {}}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "text/event-stream")
+		if test.lastEventID != "" {
+			r.Header.Set("Last-Event-ID", test.lastEventID)
+		}
+		response, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "text/event-stream; charset=utf-8" {
+			t.Fatalf("Unexpected response: %v %v", response.StatusCode, response.Header)
+		}
+		reader := bufio.NewReader(response.Body)
+		var events []event
+		for range test.expected {
+			events = append(events, readEvent(t, reader))
+		}
+		response.Body.Close()
+		if !reflect.DeepEqual(test.expected, events) {
+			t.Fatalf("Expected events %v, got %v", test.expected, events)
+		}
+	}
+}
+
+func TestHandler_RejectsInvalidRequests(t *testing.T) {
+	server := httptest.NewServer(sse.New(&sse.Config{Schema: testSchema(t, nil)}))
+	defer server.Close()
+
+	tests := []struct {
+		query, accept string
+		status        int
+		code          string
+	}{
+		{"{ hello }", "text/event-stream", http.StatusOK, ""},
+		{"mutation { ok }", "text/event-stream", http.StatusMethodNotAllowed, gqlerrors.ErrCodeOperationNotAllowed},
+		{"subscription { unknown }", "text/event-stream", http.StatusBadRequest, gqlerrors.ErrCodeValidationFailed},
+		{"{ hello }", "application/json", http.StatusNotAcceptable, gqlerrors.ErrCodeBadRequest},
+	}
+	for _, test := range tests {
+		r, _ := http.NewRequest("GET", server.URL+"?"+url.Values{"query": {test.query}}.Encode(), nil)
+		r.Header.Set("Accept", test.accept)
+		response, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if response.StatusCode != test.status || !strings.Contains(string(body), test.code) {
+			t.Fatalf("Unexpected response for %v: %v %s", test.query, response.StatusCode, body)
+		}
+	}
+}
+
+func TestHandler_ExecutesTrustedDocuments(t *testing.T) {
+	config := testSchemaConfig(nil)
+	docs, err := graphql.NewTrustedDocuments(map[string]string{
+		"Counter": "subscription { counter(to: 2) }",
+		"Ok":      "mutation { ok }",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.TrustedDocuments = docs
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	server := httptest.NewServer(sse.New(&sse.Config{Schema: &schema, HeartbeatInterval: -1}))
+	defer server.Close()
+
+	get := func(documentID string) *http.Response {
+		r, _ := http.NewRequest("GET", server.URL+"?"+url.Values{"documentId": {documentID}}.Encode(), nil)
+		r.Header.Set("Accept", "text/event-stream")
+		response, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	response := get("Counter")
+	defer response.Body.Close()
+	reader := bufio.NewReader(response.Body)
+	expected := []event{
+		{name: "next", data: `{"data":{"counter":1}}`},
+		{name: "next", data: `{"data":{"counter":2}}`},
+		{name: "complete"},
+	}
+	var events []event
+	for range expected {
+		events = append(events, readEvent(t, reader))
+	}
+	if !reflect.DeepEqual(expected, events) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+
+	response = get("Ok")
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected the mutations to be rejected, got %v", response.StatusCode)
+	}
+}
+
+func TestHandler_CancelsTheOperationsOfDisconnectedClients(t *testing.T) {
+	stopped := make(chan struct{})
+	server := httptest.NewServer(sse.New(&sse.Config{Schema: testSchema(t, stopped)}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, _ := http.NewRequest("POST", server.URL, strings.NewReader(`{"query": "subscription { forever }"}`))
+	r = r.WithContext(ctx)
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "text/event-stream")
+	response, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if e := readEvent(t, bufio.NewReader(response.Body)); e.name != "next" {
+		t.Fatalf("Unexpected event: %v", e)
+	}
+	cancel()
+	waitStopped(t, stopped)
+}
+
+func TestHandler_StreamsTheResultsOfSingleConnections(t *testing.T) {
+	stopped := make(chan struct{})
+	server := httptest.NewServer(sse.New(&sse.Config{Schema: testSchema(t, stopped), HeartbeatInterval: 10 * time.Millisecond}))
+	defer server.Close()
+
+	do := func(method, path, token, body string) *http.Response {
+		r, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "text/event-stream")
+		if token != "" {
+			r.Header.Set(sse.TokenHeader, token)
+		}
+		response, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	response := do("PUT", "", "", "")
+	token, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusCreated || len(token) == 0 {
+		t.Fatalf("Unexpected response: %v %s", response.StatusCode, token)
+	}
+
+	if response := do("POST", "", string(token), `{"query": "{ hello }", "extensions": {"operationId": "a"}}`); response.StatusCode != http.StatusConflict {
+		t.Fatalf("Expected the operations to require an open stream, got %v", response.StatusCode)
+	}
+
+	stream := do("GET", "", string(token), "")
+	defer stream.Body.Close()
+	if stream.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected response: %v", stream.StatusCode)
+	}
+	if response := do("GET", "", string(token), ""); response.StatusCode != http.StatusConflict {
+		t.Fatalf("Expected the stream to be opened once, got %v", response.StatusCode)
+	}
+	reader := bufio.NewReader(stream.Body)
+	if response := do("POST", "", string(token), `{"query": "subscription { counter(to: 1) }", "extensions": {"operationId": "a"}}`); response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Unexpected response: %v", response.StatusCode)
+	}
+	expected := []event{
+		{name: "next", data: `{"id":"a","payload":{"data":{"counter":1}}}`},
+		{name: "complete", data: `{"id":"a"}`},
+	}
+	var events []event
+	for range expected {
+		e := readEvent(t, reader)
+		for e.comment {
+			e = readEvent(t, reader)
+		}
+		events = append(events, e)
+	}
+	if !reflect.DeepEqual(expected, events) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+
+	if response := do("POST", "", string(token), `{"query": "subscription { forever }", "extensions": {"operationId": "b"}}`); response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Unexpected response: %v", response.StatusCode)
+	}
+	var data map[string]interface{}
+	for data["id"] != "b" {
+		e := readEvent(t, reader)
+		json.Unmarshal([]byte(e.data), &data)
+	}
+	if response := do("DELETE", "?operationId=b&token="+string(token), "", ""); response.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected response: %v", response.StatusCode)
+	}
+	waitStopped(t, stopped)
+	// the stream is kept alive by heartbeats
+	for !readEvent(t, reader).comment {
+	}
+
+	if response := do("POST", "", string(token), `{"query": "subscription { forever }", "extensions": {"operationId": "c"}}`); response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Unexpected response: %v", response.StatusCode)
+	}
+	stream.Body.Close()
+	waitStopped(t, stopped)
+}
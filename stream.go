@@ -0,0 +1,373 @@
+package graphql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DoToWriter is like Do, but writes the JSON encoded result to w while it is
+// completed, instead of building the whole result in memory. See
+// ExecuteToWriter.
+//
+// The returned error is the first error returned by w, if any. Errors of the
+// request itself are written to w as part of the result.
+func DoToWriter(p Params, w io.Writer) error {
+	AST, result := parseAndValidate(&p)
+	if result != nil {
+		return writeResult(w, result)
+	}
+	return ExecuteToWriter(executeParams(p, AST), w)
+}
+
+// ExecuteToWriter is like Execute, but writes the JSON encoded result to w
+// while it is completed, instead of building the whole result in memory.
+//
+// The fields are written in the order of the selection set, and the errors
+// are written after the data, once it is complete. The subtree of a nullable
+// field is only kept in memory when one of its selected fields is non-null,
+// since it may then be nulled out by an error after it started to be written.
+//
+// Unlike Execute, ExecuteToWriter resolves the thunks returned by resolvers
+// as soon as they are returned, and runs in the calling goroutine: resolvers
+// are expected to observe the cancellation of the context themselves. The
+// ExecutionFinishFunc of extensions is called with the errors of the result,
+// but without its data.
+//
+// The returned error is the first error returned by w, if any. Errors of the
+// request itself are written to w as part of the result.
+func ExecuteToWriter(p ExecuteParams, w io.Writer) error {
+	if p.Context == nil {
+		p.Context = context.Background()
+	}
+	presenter := errorPresenter(p.Schema, p.ErrorPresenter)
+
+	extErrs, executionFinishFn := handleExtensionsExecutionDidStart(&p)
+	if len(extErrs) != 0 {
+		return writeResult(w, &Result{
+			Errors: extErrs,
+		})
+	}
+
+	result := &Result{}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"data":`)
+	if err := p.Context.Err(); err != nil {
+		bw.WriteString("null")
+		result.Errors = append(result.Errors, presentError(p.Context, presenter, newCodedError(err, gqlerrors.ErrCodeExecutionCanceled)))
+	} else {
+		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
+			Schema:         p.Schema,
+			Root:           p.Root,
+			AST:            p.AST,
+			OperationName:  p.OperationName,
+			Args:           p.Args,
+			Result:         result,
+			Context:        p.Context,
+			ErrorPresenter: presenter,
+			Prepared:       p.prepared,
+			FieldResolver:  p.FieldResolver,
+		})
+		if err != nil {
+			bw.WriteString("null")
+			result.Errors = append(result.Errors, presentErrors(p.Context, presenter, err)...)
+		} else {
+			streamOperation(exeContext, bw)
+			result.Errors = exeContext.Errors
+		}
+	}
+
+	extErrs = executionFinishFn(result)
+	if len(extErrs) != 0 {
+		result.Errors = append(result.Errors, extErrs...)
+	}
+	addExtensionResults(&p, result)
+
+	if len(result.Errors) != 0 {
+		bw.WriteString(`,"errors":`)
+		if err := writeJSON(bw, result.Errors); err != nil {
+			return err
+		}
+	}
+	if len(result.Extensions) != 0 {
+		bw.WriteString(`,"extensions":`)
+		if err := writeJSON(bw, result.Extensions); err != nil {
+			return err
+		}
+	}
+	bw.WriteByte('}')
+	return bw.Flush()
+}
+
+// writeResult writes the JSON encoding of a result built in memory.
+func writeResult(w io.Writer, result *Result) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// streamWriter is what streamed values are written to: either the buffered
+// writer of the response, or the buffer of a subtree which may be nulled
+// out.
+type streamWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+}
+
+// nullableBoundary is the nullable position a value is written at. A value
+// which may be nulled out after it started to be written is buffered until
+// it is complete.
+type nullableBoundary struct {
+	w   streamWriter
+	buf *bytes.Buffer
+}
+
+// writer returns the writer to write the value to, buffering it if it is
+// exposed to errors of its non-null children. Values written at non-null
+// positions, which have no boundary, are never buffered: their errors null
+// out the nearest nullable ancestor, which is buffered.
+func (b *nullableBoundary) writer(exposed bool) streamWriter {
+	if !exposed {
+		return b.w
+	}
+	b.buf = &bytes.Buffer{}
+	return b.buf
+}
+
+// flush writes the buffered value, if any.
+func (b *nullableBoundary) flush() {
+	if b.buf != nil {
+		b.w.Write(b.buf.Bytes())
+		b.buf = nil
+	}
+}
+
+func streamOperation(eCtx *executionContext, w streamWriter) {
+	operationType, fields, err := operationRootFields(eCtx, eCtx.Operation)
+	if err != nil {
+		w.WriteString("null")
+		eCtx.Errors = append(eCtx.Errors, eCtx.presentErrors(err)...)
+		return
+	}
+	source := eCtx.Root
+	if source == nil {
+		source = map[string]interface{}{}
+	}
+
+	// An error of a non-null root field nulls out the data.
+	boundary := &nullableBoundary{w: w}
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			eCtx.Errors = append(eCtx.Errors, eCtx.presentError(newCodedError(err, gqlerrors.ErrCodeInternalServerError)))
+			boundary.buf = nil
+			w.WriteString("null")
+			return
+		}
+		boundary.flush()
+	}()
+	streamFields(eCtx, boundary.writer(hasNonNullField(eCtx, operationType, fields)), operationType, source, fields, nil)
+}
+
+// hasNonNullField returns whether any of the fields is non-null, in which
+// case an error may null out the object.
+func hasNonNullField(eCtx *executionContext, parentType *Object, fields *collectedFields) bool {
+	for _, responseName := range fields.responseNames {
+		fieldDef := getFieldDef(eCtx.Schema, parentType, fieldASTName(fields.fields[responseName][0]))
+		if fieldDef == nil {
+			continue
+		}
+		if _, ok := fieldDef.Type.(*NonNull); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// streamFields writes the fields of an object.
+func streamFields(eCtx *executionContext, w streamWriter, parentType *Object, source interface{}, fields *collectedFields, path *ResponsePath) {
+	w.WriteByte('{')
+	first := true
+	for _, responseName := range fields.responseNames {
+		fieldASTs := fields.fields[responseName]
+		fieldDef := getFieldDef(eCtx.Schema, parentType, fieldASTName(fieldASTs[0]))
+		if fieldDef == nil {
+			continue
+		}
+		if !first {
+			w.WriteByte(',')
+		}
+		first = false
+		// Response names are GraphQL names, which need no escaping.
+		w.WriteByte('"')
+		w.WriteString(responseName)
+		w.WriteString(`":`)
+		streamField(eCtx, w, fieldDef, parentType, source, fieldASTs, path.WithKey(responseName))
+	}
+	w.WriteByte('}')
+}
+
+// streamField resolves a field and writes its value.
+func streamField(eCtx *executionContext, w streamWriter, fieldDef *FieldDefinition, parentType *Object, source interface{}, fieldASTs []*ast.Field, path *ResponsePath) {
+	info, result, ok := resolveFieldValueCatchingError(eCtx, fieldDef, parentType, source, fieldASTs, path)
+	if !ok {
+		w.WriteString("null")
+		return
+	}
+	streamValueCatchingError(eCtx, w, fieldDef.Type, fieldASTs, info, path, result)
+}
+
+func resolveFieldValueCatchingError(eCtx *executionContext, fieldDef *FieldDefinition, parentType *Object, source interface{}, fieldASTs []*ast.Field, path *ResponsePath) (info ResolveInfo, result interface{}, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			handleFieldError(r, FieldASTsToNodeASTs(fieldASTs), path, fieldDef.Type, eCtx)
+			ok = false
+		}
+	}()
+	info, result = resolveFieldValue(eCtx, fieldDef, parentType, source, fieldASTs, path)
+	return info, result, true
+}
+
+// streamValueCatchingError writes a value, or null if a nullable value
+// fails to complete.
+func streamValueCatchingError(eCtx *executionContext, w streamWriter, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) {
+	if _, ok := returnType.(*NonNull); ok {
+		streamValue(eCtx, w, nil, returnType, fieldASTs, info, path, result)
+		return
+	}
+	boundary := &nullableBoundary{w: w}
+	defer func() {
+		if r := recover(); r != nil {
+			handleFieldError(r, FieldASTsToNodeASTs(fieldASTs), path, returnType, eCtx)
+			boundary.buf = nil
+			w.WriteString("null")
+			return
+		}
+		boundary.flush()
+	}()
+	streamValue(eCtx, w, boundary, returnType, fieldASTs, info, path, result)
+}
+
+// streamValue writes a value, like completeValue completes it. It panics
+// before writing anything if the value fails to complete, so that the value
+// can be written as null instead, except for the errors of its non-null
+// children, in which case the value is buffered by its boundary.
+func streamValue(eCtx *executionContext, w streamWriter, boundary *nullableBoundary, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) {
+	if resultVal := reflect.ValueOf(result); resultVal.IsValid() && resultVal.Kind() == reflect.Func {
+		result = resolveThunk(result)
+	}
+
+	nonNull := false
+	if nonNullType, ok := returnType.(*NonNull); ok {
+		returnType = nonNullType.OfType
+		nonNull = true
+	}
+	writeNull := func() {
+		if nonNull {
+			panic(newNonNullError(fieldASTs, info, path))
+		}
+		w.WriteString("null")
+	}
+	if isNullish(result) {
+		writeNull()
+		return
+	}
+	writer := func(exposed bool) streamWriter {
+		if boundary == nil {
+			return w
+		}
+		return boundary.writer(exposed)
+	}
+
+	switch returnType := returnType.(type) {
+	case *List:
+		resultVal := listValue(info, result)
+		itemType := returnType.OfType
+		_, exposed := itemType.(*NonNull)
+		w := writer(exposed)
+		w.WriteByte('[')
+		for i := 0; i < resultVal.Len(); i++ {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			streamValueCatchingError(eCtx, w, itemType, fieldASTs, info, path.WithKey(i), resultVal.Index(i).Interface())
+		}
+		w.WriteByte(']')
+	case *Scalar, *Enum:
+		serialized := completeLeafValue(returnType.(Leaf), result)
+		if serialized == nil {
+			writeNull()
+			return
+		}
+		if err := writeJSON(w, serialized); err != nil {
+			panic(err)
+		}
+	case *Union, *Interface:
+		runtimeType := resolveRuntimeType(eCtx, returnType.(Abstract), info, result)
+		streamObject(eCtx, writer, runtimeType, fieldASTs, info, path, result)
+	case *Object:
+		streamObject(eCtx, writer, returnType, fieldASTs, info, path, result)
+	default:
+		// Not reachable. All possible output types have been considered.
+		err := invariantf(false,
+			`Cannot complete value of unexpected type "%v."`, returnType)
+		panic(gqlerrors.FormatError(err))
+	}
+}
+
+func streamObject(eCtx *executionContext, writer func(exposed bool) streamWriter, returnType *Object, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) {
+	fields := collectSubFields(eCtx, returnType, fieldASTs, info, result)
+	w := writer(hasNonNullField(eCtx, returnType, fields))
+	streamFields(eCtx, w, returnType, result, fields, path)
+}
+
+// writeJSON writes the JSON encoding of a value.
+func writeJSON(w streamWriter, value interface{}) error {
+	switch value := value.(type) {
+	case string:
+		// Only the strings which need escaping are encoded by encoding/json.
+		if !needsJSONEscaping(value) {
+			w.WriteByte('"')
+			w.WriteString(value)
+			w.WriteByte('"')
+			return nil
+		}
+	case bool:
+		w.WriteString(strconv.FormatBool(value))
+		return nil
+	case int:
+		w.WriteString(strconv.Itoa(value))
+		return nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func needsJSONEscaping(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/benchutil"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func streamSchema(t testing.TB) graphql.Schema {
+	itemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"nonNullName": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(map[string]interface{})["name"], nil
+				},
+			},
+			"error": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("item error")
+				},
+			},
+			"nonNullError": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("non-null item error")
+				},
+			},
+		},
+	})
+	items := []interface{}{
+		map[string]interface{}{"name": "a"},
+		map[string]interface{}{},
+		map[string]interface{}{"name": "<c>"},
+	}
+	itemsResolver := func(p graphql.ResolveParams) (interface{}, error) {
+		return items, nil
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"item": &graphql.Field{
+					Type: itemType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return items[0], nil
+					},
+				},
+				"items": &graphql.Field{
+					Type:    graphql.NewList(itemType),
+					Resolve: itemsResolver,
+				},
+				"nonNullItems": &graphql.Field{
+					Type:    graphql.NewList(graphql.NewNonNull(itemType)),
+					Resolve: itemsResolver,
+				},
+				"thunk": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return func() (interface{}, error) {
+							return "thunked", nil
+						}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func expectStreamedLikeDo(t *testing.T, schema graphql.Schema, query string) {
+	t.Helper()
+	expected, err := json.Marshal(graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  query,
+		OrderedResults: true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := graphql.DoToWriter(graphql.Params{
+		Schema:        schema,
+		RequestString: query,
+	}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != string(expected) {
+		t.Fatalf("Unexpected result:\n%v\nexpected:\n%v", buf.String(), string(expected))
+	}
+}
+
+func TestDoToWriter_WritesLikeDo(t *testing.T) {
+	schema := streamSchema(t)
+	for _, query := range []string{
+		`{ items { name } item { name nonNullName } }`,
+		`{ item { name error } }`,
+		`{ item { name nonNullError } items { name } }`,
+		`{ items { name nonNullName } }`,
+		`{ nonNullItems { name nonNullName } item { name } }`,
+		`{ thunk }`,
+		`{ item { unknown } }`,
+		`{ item `,
+	} {
+		expectStreamedLikeDo(t, schema, query)
+	}
+}
+
+func TestDoToWriter_StarWars(t *testing.T) {
+	expectStreamedLikeDo(t, testutil.StarWarsSchema, `
+		query {
+			hero {
+				__typename
+				name
+				friends {
+					name
+					... on Human {
+						homePlanet
+					}
+				}
+			}
+			luke: human(id: "1000") {
+				name
+				appearsIn
+			}
+		}
+	`)
+}
+
+func TestDoToWriter_NullsOutDataForNonNullRootField(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "name", nil
+					},
+				},
+				"nonNull": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	var buf bytes.Buffer
+	if err := graphql.DoToWriter(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ name nonNull }`,
+	}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Data   interface{}
+		Errors []map[string]interface{}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("unexpected error: %v in %v", err, buf.String())
+	}
+	if result.Data != nil || len(result.Errors) != 1 {
+		t.Fatalf("Unexpected result: %v", buf.String())
+	}
+}
+
+func BenchmarkListQuery_ToWriter_10K(b *testing.B) {
+	schema := benchutil.ListSchemaWithXItems(10 * 1000)
+	query := `
+		query {
+			colors {
+				hex
+				r
+				g
+				b
+			}
+		}
+	`
+	var buf bytes.Buffer
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		err := graphql.DoToWriter(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+		}, &buf)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
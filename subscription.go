@@ -3,10 +3,10 @@ package graphql
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/graphql-go/graphql/gqlerrors"
-	"github.com/graphql-go/graphql/language/parser"
-	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
 // SubscribeParams parameters for subscribing
@@ -14,53 +14,106 @@ type SubscribeParams struct {
 	Schema        Schema
 	RequestString string
 	RootValue     interface{}
-	// ContextValue    context.Context
-	VariableValues  map[string]interface{}
-	OperationName   string
-	FieldResolver   FieldResolveFn
+	// Context may be provided to pass application-specific per-request information to the
+	// subscribe and resolve functions, and ends the subscription once done
+	Context        context.Context
+	VariableValues map[string]interface{}
+	OperationName  string
+	// FieldResolver, if set, is the resolve function of the fields without one, instead of
+	// DefaultResolveFn
+	FieldResolver FieldResolveFn
+	// FieldSubscriber, if set, is the Subscribe function of the root subscription fields
+	// without one
 	FieldSubscriber FieldResolveFn
 }
 
-// Subscribe performs a subscribe operation on the given query and schema
-// To finish a subscription you can simply close the channel from inside the `Subscribe` function
-// currently does not support extensions hooks
-func Subscribe(p Params) chan *Result {
+// SourceStream is a stream of events, the source of the results of a subscription, which the
+// Subscribe function of its root field can return, as can it return a `chan interface{}` whose
+// events are the values received from it, and the error values event errors
+type SourceStream interface {
+	// Next returns the next event, blocking until there is one or the context is done. It
+	// returns io.EOF once the stream ends, and other errors are the errors of single events,
+	// the stream going on
+	Next(ctx context.Context) (interface{}, error)
+
+	// Close releases the resources of the stream, e.g. its connection to a broker. It is
+	// called once the stream ends, or its subscription is canceled
+	Close() error
+}
 
-	source := source.NewSource(&source.Source{
-		Body: []byte(p.RequestString),
-		Name: "GraphQL request",
-	})
+// channelSourceStream is the SourceStream of a channel of events
+type channelSourceStream <-chan interface{}
 
-	// TODO run extensions hooks
+func (c channelSourceStream) Next(ctx context.Context) (interface{}, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case event, ok := <-c:
+		if !ok {
+			return nil, io.EOF
+		}
+		if err, ok := event.(error); ok {
+			return nil, err
+		}
+		return event, nil
+	}
+}
 
-	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
-	if err != nil {
+func (c channelSourceStream) Close() error {
+	return nil
+}
 
-		// merge the errors from extensions and the original error from parser
-		return sendOneResultAndClose(&Result{
-			Errors: gqlerrors.FormatErrors(err),
-		})
+// valueSourceStream is the SourceStream of a single event
+type valueSourceStream struct {
+	value interface{}
+	done  bool
+}
+
+func (s *valueSourceStream) Next(ctx context.Context) (interface{}, error) {
+	if s.done {
+		return nil, io.EOF
 	}
+	s.done = true
+	return s.value, nil
+}
 
-	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+func (s *valueSourceStream) Close() error {
+	return nil
+}
 
-	if !validationResult.IsValid {
-		// run validation finish functions for extensions
-		return sendOneResultAndClose(&Result{
-			Errors: validationResult.Errors,
-		})
+// Subscribe performs a subscribe operation on the given query and schema
+// To finish a subscription you can simply close the channel from inside the `Subscribe` function
+// Consumers which stop receiving the results must cancel the context of the params, which closes
+// the source stream of the subscription
+// The extensions are initialized, and notified about the parse and the validation, once per
+// subscription, and about the execution of each event
+func Subscribe(p Params) chan *Result {
+	AST, result := parseAndValidate(&p)
+	if result != nil {
+		return sendOneResultAndClose(result)
+	}
+	return ExecuteSubscription(executeParams(p, AST))
+}
 
+// SubscribeWithParams is like Subscribe, with the default subscribe and resolve functions of
+// the SubscribeParams
+func SubscribeWithParams(p SubscribeParams) chan *Result {
+	params := Params{
+		Schema:         p.Schema,
+		RequestString:  p.RequestString,
+		VariableValues: p.VariableValues,
+		OperationName:  p.OperationName,
+		Context:        p.Context,
 	}
-	return ExecuteSubscription(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
-	})
+	AST, result := parseAndValidate(&params)
+	if result != nil {
+		return sendOneResultAndClose(result)
+	}
+	executeParams := executeParams(params, AST)
+	executeParams.Root = p.RootValue
+	executeParams.FieldResolver = p.FieldResolver
+	executeParams.FieldSubscriber = p.FieldSubscriber
+	return ExecuteSubscription(executeParams)
 }
 
 func sendOneResultAndClose(res *Result) chan *Result {
@@ -71,158 +124,231 @@ func sendOneResultAndClose(res *Result) chan *Result {
 }
 
 // ExecuteSubscription is similar to graphql.Execute but returns a channel instead of a Result
-// currently does not support extensions
+// The events of the source stream returned by the Subscribe function of the root field are
+// executed as the root value of the operation, and the errors of the events are reported as
+// errors of the root field, each event having its result. The channel is closed, and the source
+// stream too, once the stream ends or the context is done
+// The SubscriptionExtensions are notified about the start and the end of the subscription, and
+// the extensions about the execution of each event
 func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 	if p.Context == nil {
 		p.Context = context.Background()
 	}
+	presenter := errorPresenter(p.Schema, p.ErrorPresenter)
+
+	// run subscriptionDidStart functions from extensions
+	extErrs, subscriptionFinishFn := handleExtensionsSubscriptionDidStart(&p)
+	if len(extErrs) != 0 {
+		return sendOneResultAndClose(&Result{
+			Errors: extErrs,
+		})
+	}
 
 	var mapSourceToResponse = func(payload interface{}) *Result {
 		return Execute(ExecuteParams{
-			Schema:        p.Schema,
-			Root:          payload,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Context:       p.Context,
+			Schema:         p.Schema,
+			Root:           payload,
+			AST:            p.AST,
+			OperationName:  p.OperationName,
+			Args:           p.Args,
+			Context:        p.Context,
+			ErrorPresenter: p.ErrorPresenter,
+			OrderedResults: p.OrderedResults,
+			FieldResolver:  p.FieldResolver,
 		})
 	}
 	var resultChannel = make(chan *Result)
+	// send sends a result, unless the subscription is canceled
+	var send = func(result *Result) bool {
+		select {
+		case resultChannel <- result:
+			return true
+		case <-p.Context.Done():
+			return false
+		}
+	}
 	go func() {
+		// err is the error ending the subscription, if any
+		var err error
 		defer close(resultChannel)
 		defer func() {
-			if err := recover(); err != nil {
-				e, ok := err.(error)
+			if err == nil {
+				err = p.Context.Err()
+			}
+			if extErrs := subscriptionFinishFn(err); len(extErrs) != 0 {
+				send(&Result{Errors: extErrs})
+			}
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				e, ok := r.(error)
 				if !ok {
-					return
-				}
-				resultChannel <- &Result{
-					Errors: gqlerrors.FormatErrors(e),
+					e = fmt.Errorf("%v", r)
 				}
+				err = e
+				send(&Result{
+					Errors: presentErrors(p.Context, presenter, newCodedError(e, gqlerrors.ErrCodeInternalServerError)),
+				})
 			}
-			return
 		}()
 
-		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
-			Schema:        p.Schema,
-			Root:          p.Root,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Context:       p.Context,
-		})
-
+		var (
+			stream SourceStream
+			root   *subscriptionRootField
+		)
+		stream, root, err = createSourceStream(p)
 		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
-			}
-
+			send(&Result{
+				Errors: presentErrors(p.Context, presenter, err),
+			})
 			return
 		}
+		defer stream.Close()
 
-		operationType, err := getOperationRootType(p.Schema, exeContext.Operation)
-		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
+		for {
+			event, eventErr := stream.Next(p.Context)
+			if p.Context.Err() != nil || eventErr == io.EOF {
+				return
+			}
+			var result *Result
+			if eventErr != nil {
+				result = root.errorResult(p, presenter, eventErr)
+			} else {
+				result = mapSourceToResponse(event)
+			}
+			if !send(result) {
+				return
 			}
-
-			return
 		}
+	}()
 
-		fields := collectFields(collectFieldsParams{
-			ExeContext:   exeContext,
-			RuntimeType:  operationType,
-			SelectionSet: exeContext.Operation.GetSelectionSet(),
-		})
+	// return a result channel
+	return resultChannel
+}
 
-		responseNames := []string{}
-		for name := range fields {
-			responseNames = append(responseNames, name)
-		}
-		responseName := responseNames[0]
-		fieldNodes := fields[responseName]
-		fieldNode := fieldNodes[0]
-		fieldName := fieldNode.Name.Value
-		fieldDef := getFieldDef(p.Schema, operationType, fieldName)
-
-		if fieldDef == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription field %q is not defined", fieldName)),
-			}
+// subscriptionRootField is the root field of a subscription
+type subscriptionRootField struct {
+	responseName string
+	fieldNodes   []ast.Node
+	returnType   Output
+}
 
-			return
-		}
+// errorResult returns the result of an event error, an error of the root field
+func (f *subscriptionRootField) errorResult(p ExecuteParams, presenter ErrorPresenterFn, err error) *Result {
+	located := gqlerrors.SetCode(NewLocatedErrorWithPath(err, f.fieldNodes, []interface{}{f.responseName}), gqlerrors.ErrCodeResolverError)
+	result := &Result{
+		Errors: []gqlerrors.FormattedError{presentError(p.Context, presenter, located)},
+	}
+	if _, ok := f.returnType.(*NonNull); ok {
+		return result
+	}
+	if p.OrderedResults {
+		data := NewOrderedMap()
+		data.Set(f.responseName, nil)
+		result.Data = data
+	} else {
+		result.Data = map[string]interface{}{f.responseName: nil}
+	}
+	return result
+}
 
-		resolveFn := fieldDef.Subscribe
+// createSourceStream calls the Subscribe function of the root field of the subscription,
+// returning its source stream
+func createSourceStream(p ExecuteParams) (SourceStream, *subscriptionRootField, error) {
+	exeContext, err := buildExecutionContext(buildExecutionCtxParams{
+		Schema:         p.Schema,
+		Root:           p.Root,
+		AST:            p.AST,
+		OperationName:  p.OperationName,
+		Args:           p.Args,
+		Context:        p.Context,
+		ErrorPresenter: errorPresenter(p.Schema, p.ErrorPresenter),
+		OrderedResults: p.OrderedResults,
+		FieldResolver:  p.FieldResolver,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 
-		if resolveFn == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription function %q is not defined", fieldName)),
-			}
-			return
-		}
-		fieldPath := &ResponsePath{
-			Key: responseName,
-		}
+	operationType, err := getOperationRootType(p.Schema, exeContext.Operation)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		args := getArgumentValues(fieldDef.Args, fieldNode.Arguments, exeContext.VariableValues)
-		info := ResolveInfo{
-			FieldName:      fieldName,
-			FieldASTs:      fieldNodes,
-			Path:           fieldPath,
-			ReturnType:     fieldDef.Type,
-			ParentType:     operationType,
-			Schema:         p.Schema,
-			Fragments:      exeContext.Fragments,
-			RootValue:      exeContext.Root,
-			Operation:      exeContext.Operation,
-			VariableValues: exeContext.VariableValues,
-		}
+	fields := collectFields(collectFieldsParams{
+		ExeContext:   exeContext,
+		RuntimeType:  operationType,
+		SelectionSet: exeContext.Operation.GetSelectionSet(),
+	})
+	if len(fields.responseNames) == 0 {
+		return nil, nil, newOperationResolutionError("the subscription selects no field", []ast.Node{exeContext.Operation})
+	}
 
-		fieldResult, err := resolveFn(ResolveParams{
-			Source:  p.Root,
-			Args:    args,
-			Info:    info,
-			Context: p.Context,
-		})
-		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
-			}
+	responseName := fields.responseNames[0]
+	fieldNodes := fields.fields[responseName]
+	fieldNode := fieldNodes[0]
+	fieldName := fieldNode.Name.Value
+	fieldDef := getFieldDef(p.Schema, operationType, fieldName)
 
-			return
-		}
+	root := &subscriptionRootField{
+		responseName: responseName,
+	}
+	for _, fieldNode := range fieldNodes {
+		root.fieldNodes = append(root.fieldNodes, fieldNode)
+	}
 
-		if fieldResult == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("no field result")),
-			}
+	if fieldDef == nil {
+		return nil, nil, newOperationResolutionError(fmt.Sprintf("the subscription field %q is not defined", fieldName), root.fieldNodes)
+	}
+	root.returnType = fieldDef.Type
 
-			return
-		}
+	resolveFn := fieldDef.Subscribe
+	if resolveFn == nil {
+		resolveFn = p.FieldSubscriber
+	}
+	if resolveFn == nil {
+		return nil, nil, newCodedError(fmt.Errorf("the subscription function %q is not defined", fieldName), gqlerrors.ErrCodeInternalServerError)
+	}
+	fieldPath := &ResponsePath{
+		Key: responseName,
+	}
 
-		switch fieldResult.(type) {
-		case chan interface{}:
-			sub := fieldResult.(chan interface{})
-			for {
-				select {
-				case <-p.Context.Done():
-					return
-
-				case res, more := <-sub:
-					if !more {
-						return
-					}
-					resultChannel <- mapSourceToResponse(res)
-				}
-			}
-		default:
-			resultChannel <- mapSourceToResponse(fieldResult)
-			return
-		}
-	}()
+	args := getFieldArgumentValues(p.Schema, fieldDef, fieldNode.Arguments, exeContext.VariableValues)
+	info := ResolveInfo{
+		FieldName:      fieldName,
+		FieldASTs:      fieldNodes,
+		Path:           fieldPath,
+		ReturnType:     fieldDef.Type,
+		ParentType:     operationType,
+		Schema:         p.Schema,
+		Fragments:      exeContext.Fragments,
+		RootValue:      exeContext.Root,
+		Operation:      exeContext.Operation,
+		VariableValues: exeContext.VariableValues,
+	}
 
-	// return a result channel
-	return resultChannel
+	fieldResult, err := resolveFn(ResolveParams{
+		Source:  p.Root,
+		Args:    args,
+		Info:    info,
+		Context: p.Context,
+	})
+	if err != nil {
+		located := NewLocatedErrorWithPath(err, root.fieldNodes, []interface{}{responseName})
+		return nil, nil, gqlerrors.SetCode(located, gqlerrors.ErrCodeResolverError)
+	}
+
+	switch fieldResult := fieldResult.(type) {
+	case nil:
+		return nil, nil, newCodedError(fmt.Errorf("no field result"), gqlerrors.ErrCodeInternalServerError)
+	case SourceStream:
+		return fieldResult, root, nil
+	case chan interface{}:
+		return channelSourceStream(fieldResult), root, nil
+	case <-chan interface{}:
+		return channelSourceStream(fieldResult), root, nil
+	default:
+		return &valueSourceStream{value: fieldResult}, root, nil
+	}
 }
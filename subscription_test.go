@@ -1,11 +1,17 @@
 package graphql_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
 	"github.com/graphql-go/graphql/testutil"
 )
 
@@ -87,7 +93,10 @@ func TestSchemaSubscribe(t *testing.T) {
 				}
 			`,
 			ExpectedResults: []testutil.TestResponse{
-				{Errors: []string{"Cannot query field \"xxx\" on type \"Subscription\"."}},
+				{Errors: []string{
+					"Anonymous Subscription must select only one top level field.",
+					"Cannot query field \"xxx\" on type \"Subscription\".",
+				}},
 			},
 		},
 		{
@@ -112,6 +121,28 @@ func TestSchemaSubscribe(t *testing.T) {
 				{Errors: []string{"got a panic error"}},
 			},
 		},
+		{
+			Name: "panic of a non-error value inside subscribe is recovered",
+			Schema: makeSubscriptionSchema(t, graphql.ObjectConfig{
+				Name: "Subscription",
+				Fields: graphql.Fields{
+					"should_error": &graphql.Field{
+						Type: graphql.String,
+						Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+							panic("got a panic value")
+						},
+					},
+				},
+			}),
+			Query: `
+				subscription {
+					should_error
+				}
+			`,
+			ExpectedResults: []testutil.TestResponse{
+				{Errors: []string{"got a panic value"}},
+			},
+		},
 		{
 			Name: "subscribe with resolver changes output",
 			Schema: makeSubscriptionSchema(t, graphql.ObjectConfig{
@@ -231,6 +262,208 @@ func TestSchemaSubscribe(t *testing.T) {
 	})
 }
 
+// testSourceStream is a SourceStream of events, whose error values are event
+// errors, or of endless events if it has none.
+type testSourceStream struct {
+	events []interface{}
+	closed chan struct{}
+}
+
+func (s *testSourceStream) Next(ctx context.Context) (interface{}, error) {
+	if s.events == nil {
+		return "event", nil
+	}
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	if err, ok := event.(error); ok {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *testSourceStream) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestSubscribe_SourceStream(t *testing.T) {
+	stream := &testSourceStream{
+		events: []interface{}{"a", errors.New("event error"), "b"},
+		closed: make(chan struct{}),
+	}
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					return stream, nil
+				},
+			},
+		},
+	})
+
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { alias: sub }`,
+	}) {
+		results = append(results, result)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+	if !reflect.DeepEqual(map[string]interface{}{"alias": "a"}, results[0].Data) ||
+		!reflect.DeepEqual(map[string]interface{}{"alias": "b"}, results[2].Data) {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+	if !reflect.DeepEqual(map[string]interface{}{"alias": nil}, results[1].Data) || len(results[1].Errors) != 1 {
+		t.Fatalf("Unexpected result: %v", results[1])
+	}
+	err := results[1].Errors[0]
+	if err.Message != "event error" ||
+		!reflect.DeepEqual([]interface{}{"alias"}, err.Path) ||
+		!reflect.DeepEqual([]location.SourceLocation{{Line: 1, Column: 16}}, err.Locations) {
+		t.Fatalf("Unexpected error: %v %v %v", err.Message, err.Path, err.Locations)
+	}
+	select {
+	case <-stream.closed:
+	default:
+		t.Fatalf("Expected the source stream to be closed")
+	}
+}
+
+func TestSubscribe_ClosesTheSourceStreamOfCanceledSubscriptions(t *testing.T) {
+	stream := &testSourceStream{closed: make(chan struct{})}
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					return stream, nil
+				},
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+		Context:       ctx,
+	})
+	<-results
+	// the consumer stops receiving the results
+	cancel()
+	select {
+	case <-stream.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the source stream to be closed")
+	}
+}
+
+func TestSubscribe_CodesTheErrorsOfTheSourceStream(t *testing.T) {
+	tests := map[string]struct {
+		subscribe graphql.FieldResolveFn
+		code      string
+		path      []interface{}
+	}{
+		"error": {
+			subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, errors.New("denied")
+			},
+			code: gqlerrors.ErrCodeResolverError,
+			path: []interface{}{"sub"},
+		},
+		"panic": {
+			subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+				panic("subscribe panic")
+			},
+			code: gqlerrors.ErrCodeInternalServerError,
+		},
+		"no field result": {
+			subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, nil
+			},
+			code: gqlerrors.ErrCodeInternalServerError,
+		},
+		"no subscribe function": {
+			code: gqlerrors.ErrCodeInternalServerError,
+		},
+	}
+	for name, test := range tests {
+		schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"sub": &graphql.Field{
+					Type:      graphql.String,
+					Subscribe: test.subscribe,
+				},
+			},
+		})
+		result := <-graphql.Subscribe(graphql.Params{
+			Schema:        schema,
+			RequestString: `subscription { sub }`,
+		})
+		if result == nil || len(result.Errors) != 1 {
+			t.Fatalf("%v: unexpected result: %v", name, result)
+		}
+		if code := gqlerrors.Code(result.Errors[0]); code != test.code {
+			t.Fatalf("%v: expected code %v, got %v", name, test.code, code)
+		}
+		if !reflect.DeepEqual(test.path, result.Errors[0].Path) {
+			t.Fatalf("%v: expected path %v, got %v", name, test.path, result.Errors[0].Path)
+		}
+	}
+}
+
+func TestSubscribeWithParams_UsesTheDefaultSubscriberAndResolver(t *testing.T) {
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+
+	var results []interface{}
+	for result := range graphql.SubscribeWithParams(graphql.SubscribeParams{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+		RootValue:     "root",
+		FieldSubscriber: func(p graphql.ResolveParams) (interface{}, error) {
+			c := make(chan interface{}, 2)
+			c <- p.Source.(string) + ":" + p.Info.FieldName + ":1"
+			c <- p.Source.(string) + ":" + p.Info.FieldName + ":2"
+			close(c)
+			return c, nil
+		},
+		FieldResolver: func(p graphql.ResolveParams) (interface{}, error) {
+			return fmt.Sprintf("resolved %v", p.Source), nil
+		},
+	}) {
+		if len(result.Errors) != 0 {
+			t.Fatalf("Unexpected result: %v", result)
+		}
+		results = append(results, result.Data)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"sub": "resolved root:sub:1"},
+		map[string]interface{}{"sub": "resolved root:sub:2"},
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
 func makeSubscribeToStringFunction(elements []string) func(p graphql.ResolveParams) (interface{}, error) {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		c := make(chan interface{})
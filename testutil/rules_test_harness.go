@@ -578,6 +578,9 @@ func expectInvalidRule(t *testing.T, schema *graphql.Schema, rules []graphql.Val
 		t.Fatalf("IsValid should be false, got %v", result.IsValid)
 	}
 	for _, expectedErr := range expectedErrors {
+		if expectedErr.Extensions == nil && len(rules) == 1 {
+			expectedErr.Extensions = RuleErrorExtensions(rules[0])
+		}
 		found := false
 		for _, err := range result.Errors {
 			if EqualFormattedError(expectedErr, err) {
@@ -603,6 +606,15 @@ func ExpectFailsRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphq
 func ExpectPassesRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string) {
 	expectValidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString)
 }
+
+// RuleErrorExtensions returns the extensions of the errors reported by the given rule.
+func RuleErrorExtensions(rule graphql.ValidationRuleFn) map[string]interface{} {
+	return map[string]interface{}{
+		"code": gqlerrors.ErrCodeValidationFailed,
+		"rule": graphql.ValidationRuleName(rule),
+	}
+}
+
 func RuleError(message string, locs ...int) gqlerrors.FormattedError {
 	locations := []location.SourceLocation{}
 	for i := 0; i < len(locs); i += 2 {
@@ -147,3 +147,58 @@ func pretty(x interface{}) string {
 	}
 	return string(got)
 }
+
+// NewCountdownSubscription returns a subscription type for testing transports,
+// whose counter field emits the integers of the (from, to] range, and whose
+// forever field emits integers until canceled, then sends to stopped.
+func NewCountdownSubscription(stopped chan<- struct{}) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"counter": &graphql.Field{
+				Type: graphql.Int,
+				Args: graphql.FieldConfigArgument{
+					"from": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"to":   &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{})
+					go func() {
+						defer close(c)
+						for i := p.Args["from"].(int) + 1; i <= p.Args["to"].(int); i++ {
+							select {
+							case c <- i:
+							case <-p.Context.Done():
+								return
+							}
+						}
+					}()
+					return c, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+			"forever": &graphql.Field{
+				Type: graphql.Int,
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{})
+					go func() {
+						defer func() { stopped <- struct{}{} }()
+						for i := 0; ; i++ {
+							select {
+							case c <- i:
+							case <-p.Context.Done():
+								return
+							}
+						}
+					}()
+					return c, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// TrustedDocuments is an allowlist of the documents which can be executed
+// against a schema. When set on the SchemaConfig, Do only executes the
+// requests referencing a document by its ID in Params.DocumentID, or whose
+// request string is the text of one of the documents, and rejects the other
+// ones with a DOCUMENT_NOT_TRUSTED error.
+//
+// Unlike with the automatic persisted queries protocol, clients can not
+// register new documents.
+type TrustedDocuments struct {
+	// ReportOnly, if set, makes Do execute the requests which would be
+	// rejected, after reporting them.
+	ReportOnly bool
+
+	// Report is called with the request string of each request which would
+	// be rejected in report-only mode, e.g. to log it. The requests are not
+	// reported if it is nil.
+	Report func(ctx context.Context, requestString string)
+
+	byID   map[string]*trustedDocument
+	byText map[string]*trustedDocument
+}
+
+type trustedDocument struct {
+	id       string
+	text     string
+	document *ast.Document
+}
+
+// LoadTrustedDocuments reads a JSON manifest of trusted documents, an object
+// mapping document IDs to their text, and returns its TrustedDocuments.
+func LoadTrustedDocuments(r io.Reader) (*TrustedDocuments, error) {
+	var manifest map[string]string
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid trusted documents manifest: %v", err)
+	}
+	return NewTrustedDocuments(manifest)
+}
+
+// NewTrustedDocuments returns the TrustedDocuments of a manifest mapping
+// document IDs to their text, parsing each document. IDs of the form
+// `sha256:<hex>` must be the SHA-256 hash of the text of their document.
+func NewTrustedDocuments(manifest map[string]string) (*TrustedDocuments, error) {
+	docs := &TrustedDocuments{
+		byID:   make(map[string]*trustedDocument, len(manifest)),
+		byText: make(map[string]*trustedDocument, len(manifest)),
+	}
+	for id, text := range manifest {
+		if strings.HasPrefix(id, "sha256:") {
+			sum := sha256.Sum256([]byte(text))
+			if !strings.EqualFold(strings.TrimPrefix(id, "sha256:"), hex.EncodeToString(sum[:])) {
+				return nil, fmt.Errorf("trusted document %q does not match its hash", id)
+			}
+		}
+		AST, err := parser.Parse(parser.ParseParams{
+			Source: source.NewSource(&source.Source{
+				Body: []byte(text),
+				Name: id,
+			}),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("trusted document %q is invalid: %v", id, err)
+		}
+		doc := &trustedDocument{
+			id:       id,
+			text:     text,
+			document: AST,
+		}
+		docs.byID[id] = doc
+		docs.byText[text] = doc
+	}
+	return docs, nil
+}
+
+// validate validates the documents against the schema.
+func (docs *TrustedDocuments) validate(schema *Schema) error {
+	for id, doc := range docs.byID {
+		if result := ValidateDocument(schema, doc.document, nil); !result.IsValid {
+			return fmt.Errorf("trusted document %q is invalid: %v", id, result.Errors[0].Message)
+		}
+	}
+	return nil
+}
+
+func (docs *TrustedDocuments) report(ctx context.Context, requestString string) {
+	if docs.Report != nil {
+		docs.Report(ctx, requestString)
+	}
+}
+
+// resolveTrustedDocument returns the trusted document of the request, which
+// is nil if the schema has no TrustedDocuments or, in report-only mode, if
+// the request is not trusted. It sets the request string of the requests
+// referencing a document by its ID.
+func resolveTrustedDocument(p *Params) (*trustedDocument, error) {
+	docs := p.Schema.trustedDocuments
+	if docs == nil {
+		if p.DocumentID != "" {
+			return nil, newCodedError(
+				errors.New("Trusted documents are not supported."),
+				gqlerrors.ErrCodeTrustedDocumentNotFound,
+			)
+		}
+		return nil, nil
+	}
+
+	if p.DocumentID != "" {
+		doc, ok := docs.byID[p.DocumentID]
+		if !ok {
+			return nil, newCodedError(
+				fmt.Errorf("Unknown trusted document %q.", p.DocumentID),
+				gqlerrors.ErrCodeTrustedDocumentNotFound,
+			)
+		}
+		p.RequestString = doc.text
+		return doc, nil
+	}
+
+	if doc, ok := docs.byText[p.RequestString]; ok {
+		return doc, nil
+	}
+	if docs.ReportOnly {
+		docs.report(p.Context, p.RequestString)
+		return nil, nil
+	}
+	return nil, newCodedError(
+		errors.New("Only trusted documents can be executed."),
+		gqlerrors.ErrCodeDocumentNotTrusted,
+	)
+}
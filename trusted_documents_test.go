@@ -0,0 +1,159 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+const trustedHeroQuery = `{ hero { name } }`
+
+func trustedDocumentsSchema(t *testing.T, docs *graphql.TrustedDocuments) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:            testutil.StarWarsSchema.QueryType(),
+		TrustedDocuments: docs,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func loadTrustedDocuments(t *testing.T) *graphql.TrustedDocuments {
+	sum := sha256.Sum256([]byte(trustedHeroQuery))
+	docs, err := graphql.LoadTrustedDocuments(strings.NewReader(`{
+		"sha256:` + hex.EncodeToString(sum[:]) + `": "{ hero { name } }",
+		"HumanName": "query ($id: String!) { human(id: $id) { name } }"
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return docs
+}
+
+func TestTrustedDocuments_ExecutesTrustedDocuments(t *testing.T) {
+	schema := trustedDocumentsSchema(t, loadTrustedDocuments(t))
+
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		DocumentID:     "HumanName",
+		VariableValues: map[string]interface{}{"id": "1000"},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"human": map[string]interface{}{
+				"name": "Luke Skywalker",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: trustedHeroQuery,
+	})
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestTrustedDocuments_RejectsUntrustedRequests(t *testing.T) {
+	schema := trustedDocumentsSchema(t, loadTrustedDocuments(t))
+	for _, test := range []struct {
+		params graphql.Params
+		code   string
+	}{
+		{
+			params: graphql.Params{Schema: schema, RequestString: `{ hero { id } }`},
+			code:   gqlerrors.ErrCodeDocumentNotTrusted,
+		},
+		{
+			params: graphql.Params{Schema: schema, DocumentID: "Unknown"},
+			code:   gqlerrors.ErrCodeTrustedDocumentNotFound,
+		},
+		{
+			params: graphql.Params{Schema: testutil.StarWarsSchema, DocumentID: "HumanName"},
+			code:   gqlerrors.ErrCodeTrustedDocumentNotFound,
+		},
+	} {
+		result := graphql.Do(test.params)
+		if len(result.Errors) != 1 || gqlerrors.Code(result.Errors[0]) != test.code {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if result.Data != nil {
+			t.Fatalf("Unexpected data: %v", result.Data)
+		}
+	}
+}
+
+func TestTrustedDocuments_ReportOnly(t *testing.T) {
+	docs := loadTrustedDocuments(t)
+	var reported []string
+	docs.ReportOnly = true
+	docs.Report = func(ctx context.Context, requestString string) {
+		reported = append(reported, requestString)
+	}
+	schema := trustedDocumentsSchema(t, docs)
+	for _, query := range []string{trustedHeroQuery, `{ hero { id } }`} {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+		})
+		if len(result.Errors) != 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	}
+	if expected := []string{`{ hero { id } }`}; !reflect.DeepEqual(expected, reported) {
+		t.Fatalf("Unexpected reported documents: %v", reported)
+	}
+}
+
+func TestTrustedDocuments_RejectsInvalidManifests(t *testing.T) {
+	for _, manifest := range []string{
+		`["{ hero { name } }"]`,
+		`{"sha256:abc": "{ hero { name } }"}`,
+		`{"A": "{ hero { name }"}`,
+	} {
+		if _, err := graphql.LoadTrustedDocuments(strings.NewReader(manifest)); err == nil {
+			t.Fatalf("expected an error for %v", manifest)
+		}
+	}
+
+	docs, err := graphql.NewTrustedDocuments(map[string]string{"A": `{ hero { unknown } }`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = graphql.NewSchema(graphql.SchemaConfig{
+		Query:            testutil.StarWarsSchema.QueryType(),
+		TrustedDocuments: docs,
+	})
+	if err == nil || !strings.Contains(err.Error(), `trusted document "A" is invalid`) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestTrustedDocuments_ResolvesTheOperationOfRequests(t *testing.T) {
+	schema := trustedDocumentsSchema(t, loadTrustedDocuments(t))
+
+	request := graphql.Request{DocumentID: "HumanName", Variables: map[string]interface{}{"id": "1000"}}
+	params, result := graphql.ParseAndValidate(request.Params(context.Background(), schema))
+	if result != nil {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	if operation := params.Operation(); operation == nil || operation.Operation != "query" {
+		t.Fatalf("Unexpected operation: %v", operation)
+	}
+	request = graphql.Request{Query: `{ human(id: "1000") { name } }`}
+	if _, result := graphql.ParseAndValidate(request.Params(context.Background(), schema)); result == nil || gqlerrors.Code(result.Errors[0]) != gqlerrors.ErrCodeDocumentNotTrusted {
+		t.Fatalf("Unexpected result of an untrusted request: %v", result)
+	}
+}
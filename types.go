@@ -17,3 +17,18 @@ type Result struct {
 func (r *Result) HasErrors() bool {
 	return len(r.Errors) > 0
 }
+
+// IsRequestError reports whether the result is the one of a request which
+// was not executed, e.g. because it is invalid: it has errors, none of
+// which has a path, and no data.
+func (r *Result) IsRequestError() bool {
+	if r.Data != nil || len(r.Errors) == 0 {
+		return false
+	}
+	for _, err := range r.Errors {
+		if len(err.Path) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,318 @@
+// Package upload implements the GraphQL multipart request spec, i.e. the
+// uploads of files as the parts of multipart requests, which are set in the
+// variables of the operations:
+//
+//	https://github.com/jaydenseric/graphql-multipart-request-spec
+//
+// The files are streamed to temporary files rather than buffered in memory,
+// and are passed to the resolvers of the Upload arguments as *File values.
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// maxOperationsSize is the maximum size of the operations and map fields, as
+// is the maximum size of the url-encoded forms parsed by net/http.
+const maxOperationsSize = 10 << 20
+
+var (
+	// ErrFileTooLarge is returned by Parse for a file larger than the
+	// MaxFileSize of the Config.
+	ErrFileTooLarge = errors.New("upload: file too large")
+
+	// ErrTooManyFiles is returned by Parse for a request of more files than
+	// the MaxFiles of the Config.
+	ErrTooManyFiles = errors.New("upload: too many files")
+)
+
+// Upload is the scalar of the files of multipart requests. Its values can
+// only be set by Parse, as variables, and are parsed to *File values.
+var Upload = graphql.NewScalar(graphql.ScalarConfig{
+	Name:           "Upload",
+	Description:    "The `Upload` scalar type represents the files of multipart requests.",
+	SpecifiedByURL: "https://github.com/jaydenseric/graphql-multipart-request-spec",
+	SerializeWithError: func(value interface{}) (interface{}, error) {
+		return nil, errors.New("Upload cannot be serialized, it is an input type")
+	},
+	ParseValueWithError: func(value interface{}) (interface{}, error) {
+		if file, ok := value.(*File); ok && file != nil {
+			return file, nil
+		}
+		return nil, fmt.Errorf("Upload cannot represent value: %v, files are sent as the parts of multipart requests", value)
+	},
+	ParseLiteralWithError: func(valueAST ast.Value) (interface{}, error) {
+		return nil, errors.New("Upload values can only be sent as variables")
+	},
+})
+
+// File is an uploaded file, stored in a temporary file until the RemoveAll
+// method of its Form is called.
+type File struct {
+	// Filename is the name of the file on the client.
+	Filename string
+	// ContentType is the content type of the part of the file.
+	ContentType string
+	// Size is the size of the file in bytes.
+	Size int64
+	// Header is the header of the part of the file.
+	Header textproto.MIMEHeader
+
+	file *os.File
+}
+
+// Read reads the file from its current offset.
+func (f *File) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// ReadAt reads the file from the offset off. Unlike Read, it can be called
+// concurrently, e.g. by the resolvers of a file set in several variables.
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	return f.file.ReadAt(p, off)
+}
+
+// Seek sets the offset of the next Read.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Config are the options of Parse.
+type Config struct {
+	// MaxFileSize, if set, is the maximum size of each file in bytes.
+	MaxFileSize int64
+
+	// MaxFiles, if set, is the maximum number of files of a request.
+	MaxFiles int
+
+	// TempDir is the directory of the temporary files, the default
+	// directory for temporary files if empty.
+	TempDir string
+
+	// UseNumber makes the numbers of the variables be decoded as
+	// json.Number values, see graphql.RequestDecoder.
+	UseNumber bool
+}
+
+// Form is a parsed multipart request.
+type Form struct {
+	// Requests are the operations of the request, with their files set in
+	// their variables.
+	Requests []graphql.Request
+
+	// Batch reports whether the operations are a batch, whose results are
+	// to be sent as a JSON array.
+	Batch bool
+
+	// Files are the files of the request.
+	Files []*File
+}
+
+// RemoveAll closes and removes the temporary files of the form. It is to be
+// called once the operations are executed.
+func (form *Form) RemoveAll() error {
+	var err error
+	for _, file := range form.Files {
+		if file.file == nil {
+			continue
+		}
+		file.file.Close()
+		if e := os.Remove(file.file.Name()); e != nil && err == nil {
+			err = e
+		}
+		file.file = nil
+	}
+	return err
+}
+
+// IsMultipart reports whether r is a multipart request, to be parsed by
+// Parse.
+func IsMultipart(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "multipart/form-data"
+}
+
+// Parse parses a multipart request, whose parts are the operations field,
+// the map field and the files, in this order. The files are set in the
+// variables at the paths of the map, e.g. `variables.files.0`, prefixed by
+// the index of their operation in batches, e.g. `0.variables.file`.
+func Parse(r *http.Request, config Config) (*Form, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	form := &Form{}
+	if err := form.parse(reader, config); err != nil {
+		form.RemoveAll()
+		return nil, err
+	}
+	return form, nil
+}
+
+func (form *Form) parse(reader *multipart.Reader, config Config) error {
+	operations, err := readField(reader, "operations")
+	if err != nil {
+		return err
+	}
+	decoder := graphql.NewRequestDecoder(bytes.NewReader(operations))
+	if config.UseNumber {
+		decoder.UseNumber()
+	}
+	if form.Requests, form.Batch, err = decoder.Decode(); err != nil {
+		return fmt.Errorf("upload: invalid operations: %v", err)
+	}
+
+	mapField, err := readField(reader, "map")
+	if err != nil {
+		return err
+	}
+	var paths map[string][]string
+	if err := json.Unmarshal(mapField, &paths); err != nil {
+		return fmt.Errorf("upload: invalid map: %v", err)
+	}
+	if config.MaxFiles > 0 && len(paths) > config.MaxFiles {
+		return ErrTooManyFiles
+	}
+
+	for len(paths) > 0 {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("upload: %v", err)
+		}
+		name := part.FormName()
+		filePaths, ok := paths[name]
+		if !ok {
+			// parts which are not in the map are ignored
+			part.Close()
+			continue
+		}
+		delete(paths, name)
+		file, err := form.store(part, config)
+		if err != nil {
+			return err
+		}
+		for _, path := range filePaths {
+			if err := form.set(path, file); err != nil {
+				return err
+			}
+		}
+	}
+	for name := range paths {
+		return fmt.Errorf("upload: missing file %q of the map", name)
+	}
+	return nil
+}
+
+// readField reads the next part, which must be the field of the name.
+func readField(reader *multipart.Reader, name string) ([]byte, error) {
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, fmt.Errorf("upload: missing %v field: %v", name, err)
+	}
+	defer part.Close()
+	if part.FormName() != name {
+		return nil, fmt.Errorf("upload: expected the %v field, found %q", name, part.FormName())
+	}
+	value, err := ioutil.ReadAll(io.LimitReader(part, maxOperationsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	if len(value) > maxOperationsSize {
+		return nil, fmt.Errorf("upload: %v field too large", name)
+	}
+	return value, nil
+}
+
+// store copies a file part to a temporary file.
+func (form *Form) store(part *multipart.Part, config Config) (*File, error) {
+	defer part.Close()
+	tmp, err := ioutil.TempFile(config.TempDir, "graphql-upload-")
+	if err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	file := &File{
+		Filename:    part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Header:      part.Header,
+		file:        tmp,
+	}
+	form.Files = append(form.Files, file)
+
+	var src io.Reader = part
+	if config.MaxFileSize > 0 {
+		src = io.LimitReader(part, config.MaxFileSize+1)
+	}
+	if file.Size, err = io.Copy(tmp, src); err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	if config.MaxFileSize > 0 && file.Size > config.MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	return file, nil
+}
+
+// set sets the file in the variables of the operations at the path.
+func (form *Form) set(path string, file *File) error {
+	keys := strings.Split(path, ".")
+	request := &form.Requests[0]
+	if form.Batch {
+		i, err := strconv.Atoi(keys[0])
+		if err != nil || i < 0 || i >= len(form.Requests) {
+			return fmt.Errorf("upload: invalid path %q of the map", path)
+		}
+		request = &form.Requests[i]
+		keys = keys[1:]
+	}
+	if len(keys) < 2 || keys[0] != "variables" || request.Variables == nil {
+		return fmt.Errorf("upload: invalid path %q of the map", path)
+	}
+	var container interface{} = request.Variables
+	for i, key := range keys[1:] {
+		last := i == len(keys)-2
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[key]; !ok {
+				return fmt.Errorf("upload: invalid path %q of the map", path)
+			}
+			if last {
+				c[key] = file
+				return nil
+			}
+			container = c[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(c) {
+				return fmt.Errorf("upload: invalid path %q of the map", path)
+			}
+			if last {
+				c[index] = file
+				return nil
+			}
+			container = c[index]
+		default:
+			return fmt.Errorf("upload: invalid path %q of the map", path)
+		}
+	}
+	return nil
+}
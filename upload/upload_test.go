@@ -0,0 +1,214 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/upload"
+)
+
+type part struct {
+	name, filename, content string
+}
+
+func newRequest(t *testing.T, parts ...part) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, p := range parts {
+		var err error
+		if p.filename == "" {
+			err = writer.WriteField(p.name, p.content)
+		} else {
+			var w io.Writer
+			if w, err = writer.CreateFormFile(p.name, p.filename); err == nil {
+				_, err = w.Write([]byte(p.content))
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("POST", "/graphql", &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+var uploadSchema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"ok": &graphql.Field{Type: graphql.Boolean},
+		},
+	}),
+	Mutation: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"uploadFiles": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+				Args: graphql.FieldConfigArgument{
+					"files": &graphql.ArgumentConfig{
+						Type: graphql.NewList(graphql.NewNonNull(upload.Upload)),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var contents []interface{}
+					for _, value := range p.Args["files"].([]interface{}) {
+						file := value.(*upload.File)
+						content, err := ioutil.ReadAll(file)
+						if err != nil {
+							return nil, err
+						}
+						contents = append(contents, file.Filename+": "+string(content))
+					}
+					return contents, nil
+				},
+			},
+		},
+	}),
+})
+
+func TestParse_SetsTheFilesInTheVariables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := newRequest(t,
+		part{name: "operations", content: `{"query": "mutation($files: [Upload!]) { uploadFiles(files: $files) }", "variables": {"files": [null, null]}}`},
+		part{name: "map", content: `{"0": ["variables.files.0"], "1": ["variables.files.1"]}`},
+		part{name: "0", filename: "a.txt", content: "alpha"},
+		part{name: "1", filename: "b.txt", content: "beta"},
+	)
+	if !upload.IsMultipart(r) {
+		t.Fatalf("expected a multipart request")
+	}
+	form, err := upload.Parse(r, upload.Config{TempDir: dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if form.Batch || len(form.Requests) != 1 || len(form.Files) != 2 {
+		t.Fatalf("Unexpected form: %+v", form)
+	}
+	if file := form.Files[1]; file.Filename != "b.txt" || file.Size != 4 || file.ContentType != "application/octet-stream" {
+		t.Fatalf("Unexpected file: %+v", file)
+	}
+
+	result := graphql.Do(form.Requests[0].Params(context.Background(), uploadSchema))
+	expected := map[string]interface{}{"uploadFiles": []interface{}{"a.txt: alpha", "b.txt: beta"}}
+	if len(result.Errors) != 0 || !reflect.DeepEqual(expected, result.Data) {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+
+	if err := form.RemoveAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Fatalf("Expected the temporary files to be removed, found %v", files)
+	}
+}
+
+func TestParse_SetsTheFilesOfBatches(t *testing.T) {
+	r := newRequest(t,
+		part{name: "operations", content: `[{"query": "{ ok }"}, {"query": "mutation($files: [Upload!]) { uploadFiles(files: $files) }", "variables": {"files": [null]}}]`},
+		part{name: "map", content: `{"file": ["1.variables.files.0"]}`},
+		part{name: "file", filename: "c.txt", content: "gamma"},
+	)
+	form, err := upload.Parse(r, upload.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer form.RemoveAll()
+	if !form.Batch || len(form.Requests) != 2 {
+		t.Fatalf("Unexpected form: %+v", form)
+	}
+	files := form.Requests[1].Variables["files"].([]interface{})
+	if file, ok := files[0].(*upload.File); !ok || file.Filename != "c.txt" {
+		t.Fatalf("Unexpected variables: %v", form.Requests[1].Variables)
+	}
+}
+
+func TestParse_RejectsInvalidRequests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upload-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	operations := part{name: "operations", content: `{"query": "mutation($files: [Upload!]) { uploadFiles(files: $files) }", "variables": {"files": [null]}}`}
+	tests := []struct {
+		name   string
+		parts  []part
+		config upload.Config
+		err    error
+	}{
+		{
+			name:   "file too large",
+			parts:  []part{operations, {name: "map", content: `{"0": ["variables.files.0"]}`}, {name: "0", filename: "a.txt", content: "alpha"}},
+			config: upload.Config{MaxFileSize: 4},
+			err:    upload.ErrFileTooLarge,
+		},
+		{
+			name:   "too many files",
+			parts:  []part{operations, {name: "map", content: `{"0": ["variables.files.0"], "1": ["variables.files.0"]}`}},
+			config: upload.Config{MaxFiles: 1},
+			err:    upload.ErrTooManyFiles,
+		},
+		{
+			name:  "missing file",
+			parts: []part{operations, {name: "map", content: `{"0": ["variables.files.0"]}`}},
+		},
+		{
+			name:  "invalid path",
+			parts: []part{operations, {name: "map", content: `{"0": ["variables.files.1"]}`}, {name: "0", filename: "a.txt", content: "alpha"}},
+		},
+		{
+			name:  "map before operations",
+			parts: []part{{name: "map", content: `{}`}, operations},
+		},
+	}
+	for _, test := range tests {
+		test.config.TempDir = dir
+		form, err := upload.Parse(newRequest(t, test.parts...), test.config)
+		if err == nil || form != nil {
+			t.Fatalf("%v: expected an error, got %+v", test.name, form)
+		}
+		if test.err != nil && err != test.err {
+			t.Fatalf("%v: expected %v, got %v", test.name, test.err, err)
+		}
+		if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+			t.Fatalf("%v: expected the temporary files to be removed, found %v", test.name, files)
+		}
+	}
+}
+
+func TestUpload_CanOnlyBeSetByParse(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:         uploadSchema,
+		RequestString:  `mutation($files: [Upload!]) { uploadFiles(files: $files) }`,
+		VariableValues: map[string]interface{}{"files": []interface{}{"a.txt"}},
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	result = graphql.Do(graphql.Params{
+		Schema:        uploadSchema,
+		RequestString: `mutation { uploadFiles(files: ["a.txt"]) }`,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
@@ -1,6 +1,7 @@
 package graphql_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/graphql-go/graphql"
@@ -825,6 +826,22 @@ func TestTypeSystem_UnionTypesMustBeResolvable_AcceptsAUnionTypeDefiningResolveT
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+func TestTypeSystem_UnionTypesMustBeResolvable_AcceptsAUnionOfObjectTypesDefiningGoTypes(t *testing.T) {
+
+	_, err := schemaWithFieldType(graphql.NewUnion(graphql.UnionConfig{
+		Name: "SomeUnion",
+		Types: []*graphql.Object{graphql.NewObject(graphql.ObjectConfig{
+			Name: "GoObject",
+			Fields: graphql.Fields{
+				"f": &graphql.Field{Type: graphql.String},
+			},
+			GoTypes: []reflect.Type{reflect.TypeOf(struct{ F string }{})},
+		})},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
 func TestTypeSystem_UnionTypesMustBeResolvable_RejectsAUnionTypeNotDefiningResolveTypeOfObjectTypesNotDefiningIsTypeOf(t *testing.T) {
 
 	_, err := schemaWithFieldType(graphql.NewUnion(graphql.UnionConfig{
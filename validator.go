@@ -1,6 +1,10 @@
 package graphql
 
 import (
+	"reflect"
+	"runtime"
+	"strings"
+
 	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/kinds"
@@ -32,11 +36,11 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 	}
 
 	if schema == nil {
-		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide schema"))
+		vr.Errors = append(vr.Errors, newValidationFailedError("Must provide schema"))
 		return vr
 	}
 	if astDoc == nil {
-		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide document"))
+		vr.Errors = append(vr.Errors, newValidationFailedError("Must provide document"))
 		return vr
 	}
 
@@ -63,7 +67,7 @@ func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, r
 
 	for _, rule := range rules {
 		instance := rule(context)
-		visitors = append(visitors, instance.VisitorOpts)
+		visitors = append(visitors, visitorWithRuleName(context, ValidationRuleName(rule), instance.VisitorOpts))
 	}
 
 	// Visit the whole document with each instance of all provided rules.
@@ -71,6 +75,57 @@ func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, r
 	return context.Errors()
 }
 
+// ValidationRuleName returns the name of the given validation rule, e.g.
+// "FieldsOnCorrectTypeRule", as reported in the `rule` extension of the
+// errors it reports.
+func ValidationRuleName(rule ValidationRuleFn) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(rule).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		name = name[idx+1:]
+	}
+	if idx := strings.Index(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+// visitorWithRuleName wraps the visitor of a rule so that the errors reported
+// while it visits the document are tagged with the name of the rule.
+func visitorWithRuleName(context *ValidationContext, ruleName string, visitorOpts *visitor.VisitorOptions) *visitor.VisitorOptions {
+	visitFn := func(isLeaving bool) visitor.VisitFunc {
+		return func(p visitor.VisitFuncParams) (string, interface{}) {
+			node, ok := p.Node.(ast.Node)
+			if !ok {
+				return visitor.ActionNoChange, nil
+			}
+			fn := visitor.GetVisitFn(visitorOpts, node.GetKind(), isLeaving)
+			if fn == nil {
+				return visitor.ActionNoChange, nil
+			}
+			context.ruleName = ruleName
+			defer func() {
+				context.ruleName = ""
+			}()
+			return fn(p)
+		}
+	}
+	return &visitor.VisitorOptions{
+		Enter: visitFn(false),
+		Leave: visitFn(true),
+	}
+}
+
+// newValidationFailedError creates a formatted validation error that is not
+// reported by a rule.
+func newValidationFailedError(message string) gqlerrors.FormattedError {
+	err := gqlerrors.NewError(message, nil, "", nil, []int{}, nil)
+	return gqlerrors.FormatError(gqlerrors.SetCode(err, gqlerrors.ErrCodeValidationFailed))
+}
+
 type HasSelectionSet interface {
 	GetKind() string
 	GetLoc() *ast.Location
@@ -95,6 +150,10 @@ type ValidationContext struct {
 	recursiveVariableUsages        map[*ast.OperationDefinition][]*VariableUsage
 	recursivelyReferencedFragments map[*ast.OperationDefinition][]*ast.FragmentDefinition
 	fragmentSpreads                map[*ast.SelectionSet][]*ast.FragmentSpread
+
+	// ruleName is the name of the rule currently visiting the document, used
+	// to tag the errors it reports.
+	ruleName string
 }
 
 func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeInfo) *ValidationContext {
@@ -111,6 +170,13 @@ func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeIn
 }
 
 func (ctx *ValidationContext) ReportError(err error) {
+	if e, ok := err.(*gqlerrors.Error); ok {
+		e = gqlerrors.SetCode(e, gqlerrors.ErrCodeValidationFailed)
+		if ctx.ruleName != "" {
+			e = gqlerrors.SetExtension(e, "rule", ctx.ruleName)
+		}
+		err = e
+	}
 	formattedErr := gqlerrors.FormatError(err)
 	ctx.errors = append(ctx.errors, formattedErr)
 }
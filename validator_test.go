@@ -77,18 +77,21 @@ func TestValidator_SupportsFullValidation_ValidatesUsingACustomTypeInfo(t *testi
 			Locations: []location.SourceLocation{
 				{Line: 3, Column: 9},
 			},
+			Extensions: testutil.RuleErrorExtensions(graphql.FieldsOnCorrectTypeRule),
 		},
 		{
 			Message: `Cannot query field "furColor" on type "Cat". Did you mean "furColor"?`,
 			Locations: []location.SourceLocation{
 				{Line: 5, Column: 13},
 			},
+			Extensions: testutil.RuleErrorExtensions(graphql.FieldsOnCorrectTypeRule),
 		},
 		{
 			Message: `Cannot query field "isHousetrained" on type "Dog". Did you mean "isHousetrained"?`,
 			Locations: []location.SourceLocation{
 				{Line: 8, Column: 13},
 			},
+			Extensions: testutil.RuleErrorExtensions(graphql.FieldsOnCorrectTypeRule),
 		},
 	}
 	if !testutil.EqualFormattedErrors(expectedErrors, errors) {
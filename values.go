@@ -6,7 +6,6 @@ import (
 	"math"
 	"reflect"
 	"sort"
-	"strings"
 
 	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/graphql/language/ast"
@@ -16,23 +15,30 @@ import (
 
 // Prepares an object map of variableValues of the correct type based on the
 // provided variable definitions and arbitrary input. If the input cannot be
-// parsed to match the variable definitions, a GraphQLError will be returned.
+// parsed to match the variable definitions, the GraphQLErrors of every
+// problem of every variable will be returned, as a multiError.
 func getVariableValues(
 	schema Schema,
 	definitionASTs []*ast.VariableDefinition,
 	inputs map[string]interface{}) (map[string]interface{}, error) {
 	values := map[string]interface{}{}
+	var errs multiError
 	for _, defAST := range definitionASTs {
 		if defAST == nil || defAST.Variable == nil || defAST.Variable.Name == nil {
 			continue
 		}
 		varName := defAST.Variable.Name.Value
-		if varValue, err := getVariableValue(schema, defAST, inputs[varName]); err != nil {
-			return values, err
-		} else {
+		varValue, varErrs := getVariableValue(schema, defAST, inputs[varName])
+		for _, err := range varErrs {
+			errs = append(errs, newCodedError(err, gqlerrors.ErrCodeBadUserInput))
+		}
+		if len(varErrs) == 0 {
 			values[varName] = varValue
 		}
 	}
+	if len(errs) != 0 {
+		return values, errs
+	}
 	return values, nil
 }
 
@@ -67,17 +73,31 @@ func getArgumentValues(
 	return results
 }
 
+// Prepares an object map of the argument values of a field, as they are
+// passed to its resolver.
+func getFieldArgumentValues(
+	schema Schema, fieldDef *FieldDefinition, argASTs []*ast.Argument,
+	variableValues map[string]interface{}) map[string]interface{} {
+
+	args := getArgumentValues(fieldDef.Args, argASTs, variableValues)
+	if schema.sqlNullArguments {
+		args = sqlNullArguments(fieldDef.Args, args)
+	}
+	return args
+}
+
 // Given a variable definition, and any value of input, return a value which
-// adheres to the variable definition, or throw an error.
-func getVariableValue(schema Schema, definitionAST *ast.VariableDefinition, input interface{}) (interface{}, error) {
+// adheres to the variable definition, or the errors of every problem of the
+// input.
+func getVariableValue(schema Schema, definitionAST *ast.VariableDefinition, input interface{}) (interface{}, []error) {
 	ttype, err := typeFromAST(schema, definitionAST.Type)
 	if err != nil {
-		return nil, err
+		return nil, []error{err}
 	}
 	variable := definitionAST.Variable
 
 	if ttype == nil || !IsInputType(ttype) {
-		return "", gqlerrors.NewError(
+		return "", []error{gqlerrors.NewError(
 			fmt.Sprintf(`Variable "$%v" expected value of type `+
 				`"%v" which cannot be used as an input type.`, variable.Name.Value, printer.Print(definitionAST.Type)),
 			[]ast.Node{definitionAST},
@@ -85,48 +105,51 @@ func getVariableValue(schema Schema, definitionAST *ast.VariableDefinition, inpu
 			nil,
 			[]int{},
 			nil,
-		)
+		)}
 	}
 
-	isValid, messages := isValidInputValue(input, ttype)
-	if isValid {
-		if isNullish(input) {
-			if definitionAST.DefaultValue != nil {
-				return valueFromAST(definitionAST.DefaultValue, ttype, nil), nil
-			}
+	if isNullish(input) {
+		if _, ok := ttype.(*NonNull); ok {
+			return "", []error{gqlerrors.NewError(
+				fmt.Sprintf(`Variable "$%v" of required type `+
+					`"%v" was not provided.`, variable.Name.Value, printer.Print(definitionAST.Type)),
+				[]ast.Node{definitionAST},
+				"",
+				nil,
+				[]int{},
+				nil,
+			)}
+		}
+		if definitionAST.DefaultValue != nil {
+			return valueFromAST(definitionAST.DefaultValue, ttype, nil), nil
 		}
 		return coerceValue(ttype, input), nil
 	}
-	if isNullish(input) {
-		return "", gqlerrors.NewError(
-			fmt.Sprintf(`Variable "$%v" of required type `+
-				`"%v" was not provided.`, variable.Name.Value, printer.Print(definitionAST.Type)),
+
+	varPath := "$" + variable.Name.Value
+	inputErrs := inputValueErrors(input, ttype, varPath)
+	if len(inputErrs) == 0 {
+		return coerceValue(ttype, input), nil
+	}
+	errs := make([]error, 0, len(inputErrs))
+	for _, inputErr := range inputErrs {
+		// convert input interface into string for error message
+		bts, _ := json.Marshal(inputErr.value)
+		at := ""
+		if inputErr.path != varPath {
+			at = fmt.Sprintf(` at "%v"`, inputErr.path)
+		}
+		errs = append(errs, gqlerrors.NewError(
+			fmt.Sprintf(`Variable "$%v" got invalid value %v%v; %v`,
+				variable.Name.Value, string(bts), at, inputErr.message),
 			[]ast.Node{definitionAST},
 			"",
 			nil,
 			[]int{},
 			nil,
-		)
-	}
-	// convert input interface into string for error message
-	bts, _ := json.Marshal(input)
-	var (
-		inputStr = string(bts)
-		msg      string
-	)
-	if len(messages) > 0 {
-		msg = "\n" + strings.Join(messages, "\n")
+		))
 	}
-
-	return "", gqlerrors.NewError(
-		fmt.Sprintf(`Variable "$%v" got invalid value `+
-			`%v.%v`, variable.Name.Value, inputStr, msg),
-		[]ast.Node{definitionAST},
-		"",
-		nil,
-		[]int{},
-		nil,
-	)
+	return "", errs
 }
 
 // Given a type and any value, return a runtime value coerced to match the type.
@@ -207,47 +230,56 @@ func typeFromAST(schema Schema, inputTypeAST ast.Type) (Type, error) {
 	}
 }
 
-// isValidInputValue alias isValidJSValue
+// inputValueError is a reason why an input value is not accepted for its
+// type, along with the path of the part of the value it is about, e.g.
+// `$input.items[3].price`.
+type inputValueError struct {
+	path    string
+	value   interface{}
+	message string
+}
+
+// inputValueErrors alias isValidJSValue
 // Given a value and a GraphQL type, determine if the value will be
-// accepted for that type. This is primarily useful for validating the
-// runtime values of query variables.
-func isValidInputValue(value interface{}, ttype Input) (bool, []string) {
+// accepted for that type, returning every reason why it is not. This is
+// primarily useful for validating the runtime values of query variables.
+func inputValueErrors(value interface{}, ttype Input, path string) []inputValueError {
 	if isNullish(value) {
 		if ttype, ok := ttype.(*NonNull); ok {
+			message := "Expected non-null value, found null."
 			if ttype.OfType.Name() != "" {
-				return false, []string{fmt.Sprintf(`Expected "%v!", found null.`, ttype.OfType.Name())}
+				message = fmt.Sprintf(`Expected "%v!", found null.`, ttype.OfType.Name())
 			}
-			return false, []string{"Expected non-null value, found null."}
+			return []inputValueError{{path: path, value: value, message: message}}
 		}
-		return true, nil
+		return nil
 	}
 	switch ttype := ttype.(type) {
 	case *NonNull:
-		return isValidInputValue(value, ttype.OfType)
+		return inputValueErrors(value, ttype.OfType, path)
 	case *List:
 		valType := reflect.ValueOf(value)
 		if valType.Kind() == reflect.Ptr {
 			valType = valType.Elem()
 		}
 		if valType.Kind() == reflect.Slice {
-			messagesReduce := []string{}
+			var errs []inputValueError
 			for i := 0; i < valType.Len(); i++ {
 				val := valType.Index(i).Interface()
-				_, messages := isValidInputValue(val, ttype.OfType)
-				for idx, message := range messages {
-					messagesReduce = append(messagesReduce, fmt.Sprintf(`In element #%v: %v`, idx+1, message))
-				}
+				errs = append(errs, inputValueErrors(val, ttype.OfType, fmt.Sprintf("%v[%v]", path, i))...)
 			}
-			return (len(messagesReduce) == 0), messagesReduce
+			return errs
 		}
-		return isValidInputValue(value, ttype.OfType)
+		return inputValueErrors(value, ttype.OfType, path)
 
 	case *InputObject:
-		messagesReduce := []string{}
-
 		valueMap, ok := value.(map[string]interface{})
 		if !ok {
-			return false, []string{fmt.Sprintf(`Expected "%v", found not an object.`, ttype.Name())}
+			return []inputValueError{{
+				path:    path,
+				value:   value,
+				message: fmt.Sprintf(`Expected "%v", found not an object.`, ttype.Name()),
+			}}
 		}
 		fields := ttype.Fields()
 
@@ -266,33 +298,49 @@ func isValidInputValue(value interface{}, ttype Input) (bool, []string) {
 		sort.Strings(valueMapFieldNames)
 
 		// Ensure every provided field is defined.
+		var errs []inputValueError
 		for _, fieldName := range valueMapFieldNames {
 			if _, ok := fields[fieldName]; !ok {
-				messagesReduce = append(messagesReduce, fmt.Sprintf(`In field "%v": Unknown field.`, fieldName))
+				errs = append(errs, inputValueError{
+					path:    path + "." + fieldName,
+					value:   valueMap[fieldName],
+					message: fmt.Sprintf(`Field is not defined by type "%v".`, ttype.Name()),
+				})
 			}
 		}
 
 		// Ensure every defined field is valid.
 		for _, fieldName := range fieldNames {
-			_, messages := isValidInputValue(valueMap[fieldName], fields[fieldName].Type)
-			if messages != nil {
-				for _, message := range messages {
-					messagesReduce = append(messagesReduce, fmt.Sprintf(`In field "%v": %v`, fieldName, message))
-				}
-			}
+			errs = append(errs, inputValueErrors(valueMap[fieldName], fields[fieldName].Type, path+"."+fieldName)...)
 		}
-		return (len(messagesReduce) == 0), messagesReduce
+		return errs
 	case *Scalar:
-		if parsedVal := ttype.ParseValue(value); isNullish(parsedVal) {
-			return false, []string{fmt.Sprintf(`Expected type "%v", found "%v".`, ttype.Name(), value)}
+		parsedVal, err := ttype.ParseValueWithError(value)
+		if err != nil {
+			return []inputValueError{{
+				path:    path,
+				value:   value,
+				message: fmt.Sprintf(`Expected type "%v", found "%v"; %v`, ttype.Name(), value, err.Error()),
+			}}
+		}
+		if isNullish(parsedVal) {
+			return []inputValueError{{
+				path:    path,
+				value:   value,
+				message: fmt.Sprintf(`Expected type "%v", found "%v".`, ttype.Name(), value),
+			}}
 		}
 	case *Enum:
 		if parsedVal := ttype.ParseValue(value); isNullish(parsedVal) {
-			return false, []string{fmt.Sprintf(`Expected type "%v", found "%v".`, ttype.Name(), value)}
+			return []inputValueError{{
+				path:    path,
+				value:   value,
+				message: fmt.Sprintf(`Expected type "%v", found "%v".`, ttype.Name(), value),
+			}}
 		}
 	}
 
-	return true, nil
+	return nil
 }
 
 // Returns true if a value is null, undefined, or NaN.
@@ -418,13 +418,13 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnNullForNestedNon
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar","c":null}.` +
-					"\nIn field \"c\": Expected \"String!\", found null.",
+				Message: `Variable "$input" got invalid value null at "$input.c"; Expected "String!", found null.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -450,12 +450,13 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnIncorrectType(t
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Variable \"$input\" got invalid value \"foo bar\".\nExpected \"TestInputObject\", found not an object.",
+				Message: `Variable "$input" got invalid value "foo bar"; Expected "TestInputObject", found not an object.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -484,13 +485,13 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnOmissionOfNested
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar"}.` +
-					"\nIn field \"c\": Expected \"String!\", found null.",
+				Message: `Variable "$input" got invalid value null at "$input.c"; Expected "String!", found null.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -520,14 +521,22 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnDeepNestedErrors
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value {"na":{"a":"foo"}}.` +
-					"\nIn field \"na\": In field \"c\": Expected \"String!\", found null." +
-					"\nIn field \"nb\": Expected \"String!\", found null.",
+				Message: `Variable "$input" got invalid value null at "$input.na.c"; Expected "String!", found null.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 19,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
+			},
+			{
+				Message: `Variable "$input" got invalid value null at "$input.nb"; Expected "String!", found null.`,
+				Locations: []location.SourceLocation{
+					{
+						Line: 2, Column: 19,
+					},
+				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -563,13 +572,13 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnAdditionOfUnknow
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar","c":"baz","extra":"dog"}.` +
-					"\nIn field \"extra\": Unknown field.",
+				Message: `Variable "$input" got invalid value "dog" at "$input.extra"; Field is not defined by type "TestInputObject".`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -777,6 +786,7 @@ func TestVariables_NonNullableScalars_DoesNotAllowNonNullableInputsToBeOmittedIn
 						Line: 2, Column: 31,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -813,6 +823,7 @@ func TestVariables_NonNullableScalars_DoesNotAllowNonNullableInputsToBeSetToNull
 						Line: 2, Column: 31,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1038,6 +1049,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListsToBeNull(t *testi
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1186,14 +1198,13 @@ func TestVariables_ListsAndNullability_DoesNotAllowListOfNonNullsToContainNull(t
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value ` +
-					`["A",null,"B"].` +
-					"\nIn element #1: Expected \"String!\", found null.",
+				Message: `Variable "$input" got invalid value null at "$input[1]"; Expected "String!", found null.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1229,6 +1240,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListOfNonNullsToBeNull
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1288,14 +1300,13 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListOfNonNullsToContai
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" got invalid value ` +
-					`["A",null,"B"].` +
-					"\nIn element #1: Expected \"String!\", found null.",
+				Message: `Variable "$input" got invalid value null at "$input[1]"; Expected "String!", found null.`,
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1333,6 +1344,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowInvalidTypesToBeUsedAsValues(
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1371,6 +1383,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowUnknownTypesToBeUsedAsValues(
 						Line: 2, Column: 17,
 					},
 				},
+				Extensions: map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput},
 			},
 		},
 	}
@@ -1466,3 +1479,84 @@ func TestVariables_UsesArgumentDefaultValues_WhenArgumentProvidedCannotBeParsed(
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
+
+func TestVariables_ReportsEveryInvalidVariableWithItsPath(t *testing.T) {
+	itemType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Item",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"price": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+	orderType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Order",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"items": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(itemType))},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"order": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: orderType},
+						"count": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `query ($input: Order, $count: Int) {
+  order(input: $input, count: $count)
+}`,
+		VariableValues: map[string]interface{}{
+			"input": map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"name": "a", "price": 1.5},
+					nil,
+					map[string]interface{}{"price": 2},
+					map[string]interface{}{"name": "d", "price": "free", "color": "red"},
+				},
+			},
+			"count": "many",
+		},
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message: `Variable "$input" got invalid value null at "$input.items[1]"; Expected "Item!", found null.`,
+			},
+			{
+				Message: `Variable "$input" got invalid value null at "$input.items[2].name"; Expected "String!", found null.`,
+			},
+			{
+				Message: `Variable "$input" got invalid value "red" at "$input.items[3].color"; Field is not defined by type "Item".`,
+			},
+			{
+				Message: `Variable "$input" got invalid value "free" at "$input.items[3].price"; Expected type "Float", found "free"; Float cannot represent non numeric value: "free"`,
+			},
+			{
+				Message: `Variable "$count" got invalid value "many"; Expected type "Int", found "many"; Int cannot represent non-integer value: "many"`,
+				Locations: []location.SourceLocation{
+					{Line: 1, Column: 23},
+				},
+			},
+		},
+	}
+	for i := range expected.Errors {
+		if expected.Errors[i].Locations == nil {
+			expected.Errors[i].Locations = []location.SourceLocation{{Line: 1, Column: 8}}
+		}
+		expected.Errors[i].Extensions = map[string]interface{}{"code": gqlerrors.ErrCodeBadUserInput}
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -0,0 +1,333 @@
+package ws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The opcodes of the WebSocket frames, see RFC 6455, section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// The status codes of the WebSocket close frames, see RFC 6455, section
+// 7.4.1.
+const (
+	closeNormal        = 1000
+	closeProtocolError = 1002
+	closeMessageTooBig = 1009
+)
+
+// closeFrameTimeout is the time for which the close frame of a connection
+// is waited for before closing it anyway, e.g. as another write blocks.
+const closeFrameTimeout = time.Second
+
+// acceptGUID is appended to the key of the handshake to compute its accept
+// header, see RFC 6455, section 1.3.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// closeError is the error of a connection closed, or to be closed, with a
+// status code.
+type closeError struct {
+	code   int
+	reason string
+}
+
+func (err *closeError) Error() string {
+	return fmt.Sprintf("ws: connection closed: %d %v", err.code, err.reason)
+}
+
+// conn is the server side of a WebSocket connection, as specified by RFC
+// 6455, sending text messages.
+type conn struct {
+	netConn        net.Conn
+	reader         *bufio.Reader
+	maxMessageSize int64
+	writeTimeout   time.Duration
+
+	writeMu   sync.Mutex
+	writer    *bufio.Writer
+	closeOnce sync.Once
+}
+
+// upgrade completes the opening handshake of a WebSocket connection using
+// the subprotocol, which the client must offer.
+func upgrade(w http.ResponseWriter, r *http.Request, subprotocol string, checkOrigin func(*http.Request) bool, maxMessageSize int64, writeTimeout time.Duration) (*conn, error) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "the WebSocket handshake must be a GET request", http.StatusMethodNotAllowed)
+		return nil, errors.New("ws: not a GET request")
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "not a WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("ws: not a WebSocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusBadRequest)
+		return nil, errors.New("ws: unsupported WebSocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid Sec-WebSocket-Key header", http.StatusBadRequest)
+		return nil, errors.New("ws: invalid Sec-WebSocket-Key header")
+	}
+	if !headerContains(r.Header, "Sec-WebSocket-Protocol", subprotocol) {
+		http.Error(w, fmt.Sprintf("the %v subprotocol must be offered", subprotocol), http.StatusBadRequest)
+		return nil, errors.New("ws: unsupported subprotocols")
+	}
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, errors.New("ws: origin not allowed")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "the connection can not be hijacked", http.StatusInternalServerError)
+		return nil, errors.New("ws: the ResponseWriter is not a http.Hijacker")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("ws: %v", err)
+	}
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n" +
+		"Sec-WebSocket-Protocol: " + subprotocol + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("ws: %v", err)
+	}
+	return &conn{
+		netConn:        netConn,
+		reader:         rw.Reader,
+		writer:         rw.Writer,
+		maxMessageSize: maxMessageSize,
+		writeTimeout:   writeTimeout,
+	}, nil
+}
+
+// headerContains reports whether the comma-separated tokens of the header
+// contain the token, ignoring case.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOrigin reports whether the request has no Origin header, as the ones
+// of non-browser clients, or one of the host of the request, so that the
+// pages of other origins can not use the cookies of the user.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// readMessage reads the next data message, answering the pings. It returns a
+// *closeError if the client closed the connection or violated the protocol.
+func (c *conn) readMessage() ([]byte, error) {
+	var (
+		message []byte
+		started bool
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			// the close frames without status code are answered as normal
+			// closures
+			code := closeNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			return nil, &closeError{code: code, reason: string(payload[min(len(payload), 2):])}
+		case opText, opBinary:
+			if started {
+				return nil, &closeError{code: closeProtocolError, reason: "unexpected data frame"}
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, &closeError{code: closeProtocolError, reason: "unexpected continuation frame"}
+			}
+		default:
+			return nil, &closeError{code: closeProtocolError, reason: "unknown opcode"}
+		}
+		if int64(len(message)+len(payload)) > c.maxMessageSize {
+			return nil, &closeError{code: closeMessageTooBig, reason: "message too big"}
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a frame, which clients must mask.
+func (c *conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, &closeError{code: closeProtocolError, reason: "unexpected reserved bits"}
+	}
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, &closeError{code: closeProtocolError, reason: "unmasked client frame"}
+	}
+	length := uint64(header[1] & 0x7F)
+	control := opcode&0x8 != 0
+	if control && (!fin || length > 125) {
+		return false, 0, nil, &closeError{code: closeProtocolError, reason: "invalid control frame"}
+	}
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > uint64(c.maxMessageSize) {
+		return false, 0, nil, &closeError{code: closeMessageTooBig, reason: "message too big"}
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes an unfragmented, unmasked frame. It can be called
+// concurrently. The connection is closed if the frame can not be written
+// within the write timeout, e.g. as the client stopped reading, since it is
+// then partially written.
+func (c *conn) writeFrame(opcode byte, payload []byte) (err error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	defer func() {
+		if err != nil {
+			c.netConn.Close()
+		}
+	}()
+	if c.writeTimeout > 0 {
+		if err := c.netConn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return err
+		}
+	}
+	header := []byte{0x80 | opcode, 0}
+	switch length := len(payload); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+	if _, err := c.writer.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.writer.Write(payload); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+// writeText writes a text message.
+func (c *conn) writeText(message []byte) error {
+	return c.writeFrame(opText, message)
+}
+
+// close sends a close frame of the status code and reason, and closes the
+// connection. Only the first call has an effect. The connection is closed
+// without the close frame if it can not be written within
+// closeFrameTimeout, e.g. while another write blocks.
+func (c *conn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		payload := make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		// the reason of close frames, which are control frames, is limited
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		written := make(chan struct{})
+		go func() {
+			defer close(written)
+			c.writeFrame(opClose, append(payload, reason...))
+		}()
+		timer := time.NewTimer(closeFrameTimeout)
+		select {
+		case <-written:
+		case <-timer.C:
+		}
+		timer.Stop()
+		// closing the connection ends the blocked writes, and so the one of
+		// the close frame
+		c.netConn.Close()
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,407 @@
+// Package ws implements the graphql-transport-ws protocol, serving the
+// operations of a schema, and especially its subscriptions, over WebSocket
+// connections:
+//
+//	https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+//
+// A connection is initialised by a connection_init message, which can hold
+// e.g. the credentials of the client, and is acknowledged by the server. The
+// client then starts operations by subscribe messages, whose results are
+// sent as next messages until a complete message, or an error message for
+// the operations which can not be executed. Several operations can run
+// concurrently, and each is canceled when it is completed by the client or
+// when the connection is closed.
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Subprotocol is the WebSocket subprotocol of the graphql-transport-ws
+// protocol, which the clients must offer.
+const Subprotocol = "graphql-transport-ws"
+
+const (
+	// DefaultConnectionInitWaitTimeout is the default time within which the
+	// clients must initialise their connections.
+	DefaultConnectionInitWaitTimeout = 3 * time.Second
+
+	// DefaultKeepAlive is the default interval of the pings of the server.
+	DefaultKeepAlive = 15 * time.Second
+
+	// DefaultMaxMessageSize is the default maximum size in bytes of the
+	// messages of the clients.
+	DefaultMaxMessageSize = 1 << 20
+
+	// DefaultWriteTimeout is the default time within which the messages to
+	// the clients must be written.
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// The types of the messages of the protocol.
+const (
+	typeConnectionInit = "connection_init"
+	typeConnectionAck  = "connection_ack"
+	typePing           = "ping"
+	typePong           = "pong"
+	typeSubscribe      = "subscribe"
+	typeNext           = "next"
+	typeError          = "error"
+	typeComplete       = "complete"
+)
+
+// The status codes of the protocol closing the connections.
+const (
+	closeInvalidMessage         = 4400
+	closeUnauthorized           = 4401
+	closeForbidden              = 4403
+	closeInitTimeout            = 4408
+	closeSubscriberExists       = 4409
+	closeTooManyInitialisations = 4429
+)
+
+// InitFn is called with the payload of the connection_init message of a
+// connection, and returns the context of its operations, e.g. holding the
+// authenticated user. An error rejects the connection, which is closed.
+type InitFn func(ctx context.Context, r *http.Request, payload map[string]interface{}) (context.Context, error)
+
+// RootObjectFn returns the root value of the execution of an operation.
+type RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
+
+// Config are the options of a Handler.
+type Config struct {
+	// Schema is the schema served by the handler.
+	Schema *graphql.Schema
+
+	// InitFn, if set, is called with the payload of the connection_init
+	// message of each connection, and can reject it.
+	InitFn InitFn
+
+	// RootObjectFn, if set, returns the root value of the execution of each
+	// operation.
+	RootObjectFn RootObjectFn
+
+	// CheckOrigin reports whether the handshake of a connection is allowed.
+	// If nil, the handshakes whose Origin header is neither absent nor the
+	// host of the request are rejected, so that pages of other origins can
+	// not open connections with the cookies of the users.
+	CheckOrigin func(r *http.Request) bool
+
+	// ConnectionInitWaitTimeout is the time within which the clients must
+	// send their connection_init message, DefaultConnectionInitWaitTimeout
+	// if zero.
+	ConnectionInitWaitTimeout time.Duration
+
+	// KeepAlive is the interval of the ping messages sent to the clients,
+	// DefaultKeepAlive if zero. Negative values disable the pings.
+	KeepAlive time.Duration
+
+	// MaxMessageSize is the maximum size in bytes of the messages of the
+	// clients, DefaultMaxMessageSize if zero.
+	MaxMessageSize int64
+
+	// WriteTimeout is the time within which each message to a client must
+	// be written, DefaultWriteTimeout if zero, the connections of the
+	// clients which stopped reading being closed. Negative values disable
+	// the timeout.
+	WriteTimeout time.Duration
+
+	// UseNumber makes the numbers of the variables be decoded as
+	// json.Number values, see graphql.RequestDecoder.
+	UseNumber bool
+}
+
+// Handler serves a schema over WebSocket connections.
+type Handler struct {
+	config Config
+}
+
+// New returns a handler of the config, whose Schema must be set.
+func New(config *Config) *Handler {
+	if config == nil || config.Schema == nil {
+		panic("ws: undefined Schema")
+	}
+	h := &Handler{config: *config}
+	if h.config.ConnectionInitWaitTimeout == 0 {
+		h.config.ConnectionInitWaitTimeout = DefaultConnectionInitWaitTimeout
+	}
+	if h.config.KeepAlive == 0 {
+		h.config.KeepAlive = DefaultKeepAlive
+	}
+	if h.config.MaxMessageSize == 0 {
+		h.config.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if h.config.WriteTimeout == 0 {
+		h.config.WriteTimeout = DefaultWriteTimeout
+	}
+	return h
+}
+
+// IsWebSocketUpgrade reports whether r is the handshake of a WebSocket
+// connection, e.g. to serve the WebSocket connections and the other requests
+// of an endpoint by different handlers.
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") && headerContains(r.Header, "Upgrade", "websocket")
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves it
+// until it is closed.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := upgrade(w, r, Subprotocol, h.config.CheckOrigin, h.config.MaxMessageSize, h.config.WriteTimeout)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	s := &session{
+		handler:    h,
+		request:    r,
+		conn:       c,
+		ctx:        ctx,
+		operations: map[string]context.CancelFunc{},
+	}
+	defer func() {
+		cancel()
+		c.close(closeNormal, "")
+		s.wait.Wait()
+	}()
+	s.serve()
+}
+
+// message is a message of the protocol.
+type message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// session is the state of a connection.
+type session struct {
+	handler *Handler
+	request *http.Request
+	conn    *conn
+
+	// ctx is the context of the connection, until it is initialised, then
+	// the one returned by the InitFn.
+	ctx context.Context
+
+	mu          sync.Mutex
+	initialised bool
+	operations  map[string]context.CancelFunc
+	wait        sync.WaitGroup
+}
+
+// serve reads the messages of the client until the connection is closed.
+func (s *session) serve() {
+	initTimer := time.AfterFunc(s.handler.config.ConnectionInitWaitTimeout, func() {
+		// the connection is not closed while holding the lock, as closing
+		// it can block
+		s.mu.Lock()
+		initialised := s.initialised
+		s.mu.Unlock()
+		if !initialised {
+			s.conn.close(closeInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+	if keepAlive := s.handler.config.KeepAlive; keepAlive > 0 {
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		done := s.ctx.Done()
+		go func() {
+			for {
+				select {
+				case <-ticker.C:
+					s.send(message{Type: typePing})
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		data, err := s.conn.readMessage()
+		if err != nil {
+			if err, ok := err.(*closeError); ok {
+				s.conn.close(err.code, err.reason)
+			}
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			s.conn.close(closeInvalidMessage, "Invalid message received")
+			return
+		}
+		switch msg.Type {
+		case typeConnectionInit:
+			if !s.init(msg.Payload) {
+				return
+			}
+			initTimer.Stop()
+		case typePing:
+			s.send(message{Type: typePong, Payload: msg.Payload})
+		case typePong:
+		case typeSubscribe:
+			if !s.subscribe(msg) {
+				return
+			}
+		case typeComplete:
+			s.mu.Lock()
+			if cancelOperation, ok := s.operations[msg.ID]; ok {
+				cancelOperation()
+				delete(s.operations, msg.ID)
+			}
+			s.mu.Unlock()
+		default:
+			s.conn.close(closeInvalidMessage, fmt.Sprintf("Unexpected message of type %v received", msg.Type))
+			return
+		}
+	}
+}
+
+// init initialises the connection, reporting whether it is still open.
+func (s *session) init(rawPayload json.RawMessage) bool {
+	s.mu.Lock()
+	initialised := s.initialised
+	s.mu.Unlock()
+	if initialised {
+		s.conn.close(closeTooManyInitialisations, "Too many initialisation requests")
+		return false
+	}
+	var payload map[string]interface{}
+	if len(rawPayload) != 0 {
+		if err := json.Unmarshal(rawPayload, &payload); err != nil {
+			s.conn.close(closeInvalidMessage, "Invalid connection_init payload")
+			return false
+		}
+	}
+	ctx := s.ctx
+	if s.handler.config.InitFn != nil {
+		var err error
+		if ctx, err = s.handler.config.InitFn(s.ctx, s.request, payload); err != nil {
+			s.conn.close(closeForbidden, "Forbidden")
+			return false
+		}
+	}
+	s.mu.Lock()
+	s.ctx = ctx
+	s.initialised = true
+	s.mu.Unlock()
+	return s.send(message{Type: typeConnectionAck}) == nil
+}
+
+// subscribe starts the operation of a subscribe message, reporting whether
+// the connection is still open.
+func (s *session) subscribe(msg message) bool {
+	var request graphql.Request
+	if msg.ID == "" || len(msg.Payload) == 0 {
+		s.conn.close(closeInvalidMessage, "Invalid subscribe message")
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(msg.Payload))
+	if s.handler.config.UseNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(&request); err != nil {
+		s.conn.close(closeInvalidMessage, "Invalid subscribe payload")
+		return false
+	}
+
+	s.mu.Lock()
+	if !s.initialised {
+		s.mu.Unlock()
+		s.conn.close(closeUnauthorized, "Unauthorized")
+		return false
+	}
+	if _, ok := s.operations[msg.ID]; ok {
+		s.mu.Unlock()
+		s.conn.close(closeSubscriberExists, fmt.Sprintf("Subscriber for %v already exists", msg.ID))
+		return false
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.operations[msg.ID] = cancel
+	s.wait.Add(1)
+	s.mu.Unlock()
+
+	go s.execute(ctx, msg.ID, request)
+	return true
+}
+
+// execute executes an operation, sending its results until it completes or
+// its context is canceled.
+func (s *session) execute(ctx context.Context, id string, request graphql.Request) {
+	defer s.wait.Done()
+	defer func() {
+		s.mu.Lock()
+		if cancel, ok := s.operations[id]; ok {
+			cancel()
+			delete(s.operations, id)
+		}
+		s.mu.Unlock()
+	}()
+
+	p := request.Params(ctx, *s.handler.config.Schema)
+	if s.handler.config.RootObjectFn != nil {
+		p.RootObject = s.handler.config.RootObjectFn(ctx, s.request)
+	}
+	// the operation of the persisted queries and trusted documents is the
+	// one of their resolved document
+	params, result := graphql.ParseAndValidate(p)
+	if result != nil {
+		s.sendResult(ctx, id, result)
+	} else if operation := params.Operation(); operation == nil || operation.Operation != ast.OperationTypeSubscription {
+		s.sendResult(ctx, id, graphql.Execute(params))
+	} else {
+		for result := range graphql.ExecuteSubscription(params) {
+			// the results of canceled operations are drained, so that their
+			// execution ends
+			if ctx.Err() == nil {
+				s.sendResult(ctx, id, result)
+			}
+		}
+	}
+	if ctx.Err() == nil {
+		s.send(message{ID: id, Type: typeComplete})
+	}
+}
+
+// sendResult sends the result of an operation as a next message, or as an
+// error message if the operation was not executed, which completes it.
+func (s *session) sendResult(ctx context.Context, id string, result *graphql.Result) {
+	if result.IsRequestError() {
+		payload, _ := json.Marshal(result.Errors)
+		s.send(message{ID: id, Type: typeError, Payload: payload})
+		s.mu.Lock()
+		if cancel, ok := s.operations[id]; ok {
+			cancel()
+			delete(s.operations, id)
+		}
+		s.mu.Unlock()
+		return
+	}
+	payload, err := json.Marshal(result)
+	if err != nil {
+		payload, _ = json.Marshal(&graphql.Result{
+			Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())},
+		})
+	}
+	s.send(message{ID: id, Type: typeNext, Payload: payload})
+}
+
+func (s *session) send(msg message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.conn.writeText(data)
+}
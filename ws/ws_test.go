@@ -0,0 +1,339 @@
+package ws_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+	"github.com/graphql-go/graphql/ws"
+)
+
+type userKey struct{}
+
+func testSchema(t *testing.T, stopped chan<- struct{}) *graphql.Schema {
+	schema, err := graphql.NewSchema(testSchemaConfig(stopped))
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return &schema
+}
+
+func testSchemaConfig(stopped chan<- struct{}) graphql.SchemaConfig {
+	return graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Context.Value(userKey{}), nil
+					},
+				},
+			},
+		}),
+		Subscription: testutil.NewCountdownSubscription(stopped),
+	}
+}
+
+// client is a minimal client of the WebSocket protocol.
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dial(t *testing.T, server *httptest.Server, subprotocol string) (*client, *http.Response) {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r, _ := http.NewRequest("GET", server.URL, nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-WebSocket-Version", "13")
+	r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	r.Header.Set("Sec-WebSocket-Protocol", subprotocol)
+	if err := r.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	c := &client{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	response, err := http.ReadResponse(c.reader, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, response
+}
+
+func (c *client) send(message string) {
+	payload := []byte(message)
+	frame := []byte{0x81, 0x80 | byte(len(payload))}
+	if len(payload) > 125 {
+		frame = []byte{0x81, 0x80 | 126, byte(len(payload) >> 8), byte(len(payload))}
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// read reads the next frame, returning its close code for close frames.
+func (c *client) read() (map[string]interface{}, int) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(c.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	if header[0]&0x0F == 0x8 {
+		return nil, int(binary.BigEndian.Uint16(payload))
+	}
+	var message map[string]interface{}
+	if err := json.Unmarshal(payload, &message); err != nil {
+		c.t.Fatalf("Invalid message %s: %v", payload, err)
+	}
+	return message, 0
+}
+
+func (c *client) expect(expected map[string]interface{}) {
+	message, code := c.read()
+	if !reflect.DeepEqual(expected, message) {
+		c.t.Fatalf("Expected message %v, got %v %v", expected, message, code)
+	}
+}
+
+func (c *client) expectClose(expected int) {
+	for {
+		message, code := c.read()
+		if message == nil {
+			if code != expected {
+				c.t.Fatalf("Expected close code %v, got %v", expected, code)
+			}
+			return
+		}
+	}
+}
+
+func TestHandler_ExecutesOperations(t *testing.T) {
+	h := ws.New(&ws.Config{
+		Schema:    testSchema(t, nil),
+		KeepAlive: -1,
+		InitFn: func(ctx context.Context, r *http.Request, payload map[string]interface{}) (context.Context, error) {
+			return context.WithValue(ctx, userKey{}, payload["user"]), nil
+		},
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	c, response := dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols ||
+		response.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" ||
+		response.Header.Get("Sec-WebSocket-Protocol") != "graphql-transport-ws" {
+		t.Fatalf("Unexpected handshake response: %v %v", response.StatusCode, response.Header)
+	}
+	c.send(`{"type": "connection_init", "payload": {"user": "alice"}}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+
+	c.send(`{"type": "ping"}`)
+	c.expect(map[string]interface{}{"type": "pong"})
+
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"query": "{ user }"}}`)
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"user": "alice"}}})
+	c.expect(map[string]interface{}{"id": "1", "type": "complete"})
+
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"query": "subscription($to: Int) { counter(to: $to) }", "variables": {"to": 2}}}`)
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"counter": 1.0}}})
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"counter": 2.0}}})
+	c.expect(map[string]interface{}{"id": "1", "type": "complete"})
+
+	c.send(`{"id": "2", "type": "subscribe", "payload": {"query": "subscription { unknown }"}}`)
+	message, _ := c.read()
+	if errs, _ := message["payload"].([]interface{}); message["id"] != "2" || message["type"] != "error" || len(errs) != 1 {
+		t.Fatalf("Unexpected message: %v", message)
+	}
+}
+
+func TestHandler_StreamsTheSubscriptionsOfTrustedDocuments(t *testing.T) {
+	config := testSchemaConfig(nil)
+	docs, err := graphql.NewTrustedDocuments(map[string]string{
+		"Counter": "subscription { counter(to: 2) }",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.TrustedDocuments = docs
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	server := httptest.NewServer(ws.New(&ws.Config{Schema: &schema, KeepAlive: -1}))
+	defer server.Close()
+
+	c, _ := dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	c.send(`{"type": "connection_init"}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"documentId": "Counter"}}`)
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"counter": 1.0}}})
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"counter": 2.0}}})
+	c.expect(map[string]interface{}{"id": "1", "type": "complete"})
+}
+
+func TestHandler_CancelsCompletedOperations(t *testing.T) {
+	stopped := make(chan struct{})
+	server := httptest.NewServer(ws.New(&ws.Config{Schema: testSchema(t, stopped), KeepAlive: -1}))
+	defer server.Close()
+
+	waitStopped := func() {
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the subscription to be canceled")
+		}
+	}
+
+	c, _ := dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	c.send(`{"type": "connection_init"}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"query": "subscription { forever }"}}`)
+	c.expect(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": map[string]interface{}{"forever": 0.0}}})
+	c.send(`{"id": "1", "type": "complete"}`)
+	waitStopped()
+
+	c.send(`{"id": "2", "type": "subscribe", "payload": {"query": "subscription { forever }"}}`)
+	// the results of the first subscription sent before its completion can
+	// precede the ones of the second
+	for {
+		message, _ := c.read()
+		if message["id"] == "2" {
+			break
+		}
+	}
+	c.send(`{"id": "2", "type": "subscribe", "payload": {"query": "subscription { forever }"}}`)
+	c.expectClose(4409)
+	waitStopped()
+
+	c, _ = dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	c.send(`{"type": "connection_init"}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"query": "subscription { forever }"}}`)
+	c.read()
+	c.conn.Close()
+	waitStopped()
+}
+
+func TestHandler_ClosesConnectionsViolatingTheProtocol(t *testing.T) {
+	h := ws.New(&ws.Config{
+		Schema:                    testSchema(t, nil),
+		KeepAlive:                 -1,
+		ConnectionInitWaitTimeout: 50 * time.Millisecond,
+		InitFn: func(ctx context.Context, r *http.Request, payload map[string]interface{}) (context.Context, error) {
+			if payload["token"] != "secret" {
+				return nil, errors.New("invalid token")
+			}
+			return ctx, nil
+		},
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	const (
+		init      = `{"type": "connection_init", "payload": {"token": "secret"}}`
+		subscribe = `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { counter(to: 1) }"}}`
+	)
+	tests := []struct {
+		name     string
+		messages []string
+		code     int
+	}{
+		{"invalid message", []string{`{"type": 1}`}, 4400},
+		{"unknown message", []string{`{"type": "unknown"}`}, 4400},
+		{"subscribe before init", []string{subscribe}, 4401},
+		{"forbidden", []string{`{"type": "connection_init", "payload": {"token": "invalid"}}`}, 4403},
+		{"init timeout", nil, 4408},
+		{"duplicate init", []string{init, init}, 4429},
+	}
+	for _, test := range tests {
+		c, _ := dial(t, server, "graphql-transport-ws")
+		for _, message := range test.messages {
+			c.send(message)
+		}
+		c.expectClose(test.code)
+		c.conn.Close()
+	}
+
+	_, response := dial(t, server, "graphql-ws")
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected the other subprotocols to be rejected, got %v", response.StatusCode)
+	}
+}
+
+func TestHandler_SendsPings(t *testing.T) {
+	server := httptest.NewServer(ws.New(&ws.Config{Schema: testSchema(t, nil), KeepAlive: 10 * time.Millisecond}))
+	defer server.Close()
+
+	c, _ := dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	c.send(`{"type": "connection_init"}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+	c.expect(map[string]interface{}{"type": "ping"})
+}
+
+func TestHandler_ClosesTheConnectionsOfClientsWhichStopReading(t *testing.T) {
+	stopped := make(chan struct{}, 1)
+	h := ws.New(&ws.Config{
+		Schema:       testSchema(t, stopped),
+		KeepAlive:    -1,
+		WriteTimeout: 50 * time.Millisecond,
+	})
+	served := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(served)
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	c, _ := dial(t, server, "graphql-transport-ws")
+	defer c.conn.Close()
+	c.conn.(*net.TCPConn).SetReadBuffer(1024)
+	c.send(`{"type": "connection_init"}`)
+	c.expect(map[string]interface{}{"type": "connection_ack"})
+	// the client stops reading the results, whose writes then block
+	c.send(`{"id": "1", "type": "subscribe", "payload": {"query": "subscription { forever }"}}`)
+	select {
+	case <-served:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Expected the connection to be closed")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the subscription to be canceled")
+	}
+}